@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"pc-builder/backend/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const benchmarkDateLayout = "2006-01-02"
+
+type BenchmarkController struct {
+	repo       *repositories.BenchmarkRepository
+	components *repositories.ComponentRepository
+	games      *repositories.GameRepository
+	db         *gorm.DB
+}
+
+func NewBenchmarkController(db *gorm.DB) *BenchmarkController {
+	return &BenchmarkController{
+		repo:       repositories.NewBenchmarkRepository(db),
+		components: repositories.NewComponentRepository(db),
+		games:      repositories.NewGameRepository(db),
+		db:         db,
+	}
+}
+
+// BenchmarkInput is one benchmark of a create or bulk import request
+type BenchmarkInput struct {
+	ComponentID string  `json:"component_id" binding:"required"`
+	Name        string  `json:"name" binding:"required,max=100"`
+	Score       float64 `json:"score" binding:"required,gt=0"`
+	Source      string  `json:"source" binding:"max=255"`
+	MeasuredAt  string  `json:"measured_at"` // YYYY-MM-DD, defaults to today
+}
+
+func (input BenchmarkInput) toModel() (*models.Benchmark, error) {
+	measuredAt := time.Now().Truncate(24 * time.Hour)
+	if input.MeasuredAt != "" {
+		parsed, err := time.Parse(benchmarkDateLayout, input.MeasuredAt)
+		if err != nil {
+			return nil, fmt.Errorf("measured_at must use the YYYY-MM-DD format")
+		}
+		measuredAt = parsed
+	}
+
+	return &models.Benchmark{
+		ComponentID: input.ComponentID,
+		Name:        strings.TrimSpace(input.Name),
+		Score:       input.Score,
+		Source:      strings.TrimSpace(input.Source),
+		MeasuredAt:  measuredAt,
+	}, nil
+}
+
+func (ctrl *BenchmarkController) GetBenchmarks(c *gin.Context) {
+	benchmarks, err := ctrl.repo.GetBenchmarks(repositories.BenchmarkFilter{
+		ComponentID: c.Query("component_id"),
+		Name:        c.Query("name"),
+	})
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch benchmarks", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Benchmarks fetched successfully", benchmarks)
+}
+
+func (ctrl *BenchmarkController) CreateBenchmark(c *gin.Context) {
+	var request BenchmarkInput
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	benchmark, err := request.toModel()
+	if err != nil {
+		utils.BadRequestError(c, err.Error(), err)
+		return
+	}
+
+	if _, err := ctrl.components.GetComponentByID(benchmark.ComponentID); err != nil {
+		utils.BadRequestError(c, "Invalid component ID", err)
+		return
+	}
+
+	if err := ctrl.repo.CreateBenchmark(benchmark); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			utils.ConflictError(c, "Benchmark with this component, name and source already exists")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to create benchmark", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Benchmark created successfully", benchmark)
+}
+
+// BulkImportBenchmarks creates the benchmarks and overwrites the score of existing
+// component, name and source triples
+func (ctrl *BenchmarkController) BulkImportBenchmarks(c *gin.Context) {
+	var request struct {
+		Benchmarks []BenchmarkInput `json:"benchmarks" binding:"required,min=1,max=500,dive"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	type importResult struct {
+		ComponentID string `json:"component_id"`
+		Name        string `json:"name"`
+		Success     bool   `json:"success"`
+		Message     string `json:"message"`
+		Error       string `json:"error,omitempty"`
+	}
+
+	results := []importResult{}
+	totalImported := 0
+	totalFailed := 0
+
+	for _, input := range request.Benchmarks {
+		result := importResult{ComponentID: input.ComponentID, Name: input.Name}
+
+		benchmark, err := input.toModel()
+		if err != nil {
+			result.Error = err.Error()
+			result.Message = "Invalid benchmark"
+			results = append(results, result)
+			totalFailed++
+			continue
+		}
+
+		err = ctrl.repo.UpsertBenchmark(benchmark)
+		if err != nil {
+			if strings.Contains(err.Error(), "foreign key") {
+				result.Error = "Component does not exist"
+				result.Message = "Invalid component ID"
+			} else {
+				result.Error = err.Error()
+				result.Message = "Database error"
+			}
+			results = append(results, result)
+			totalFailed++
+			continue
+		}
+
+		result.Success = true
+		result.Message = "Imported successfully"
+		results = append(results, result)
+		totalImported++
+	}
+
+	status := http.StatusCreated
+	if totalImported == 0 {
+		status = http.StatusBadRequest
+	} else if totalFailed > 0 {
+		status = http.StatusMultiStatus
+	}
+
+	c.JSON(status, gin.H{
+		"status":  status,
+		"message": fmt.Sprintf("Bulk import completed: %d imported, %d failed", totalImported, totalFailed),
+		"response": gin.H{
+			"total_requested": len(request.Benchmarks),
+			"total_imported":  totalImported,
+			"total_failed":    totalFailed,
+			"results":         results,
+		},
+	})
+}
+
+func (ctrl *BenchmarkController) UpdateBenchmark(c *gin.Context) {
+	benchmark, ok := ctrl.findBenchmark(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Name       string   `json:"name" binding:"max=100"`
+		Score      *float64 `json:"score" binding:"omitempty,gt=0"`
+		Source     *string  `json:"source" binding:"omitempty,max=255"`
+		MeasuredAt string   `json:"measured_at"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if request.Name != "" {
+		updates["name"] = strings.TrimSpace(request.Name)
+	}
+	if request.Score != nil {
+		updates["score"] = *request.Score
+	}
+	if request.Source != nil {
+		updates["source"] = strings.TrimSpace(*request.Source)
+	}
+	if request.MeasuredAt != "" {
+		measuredAt, err := time.Parse(benchmarkDateLayout, request.MeasuredAt)
+		if err != nil {
+			utils.BadRequestError(c, "measured_at must use the YYYY-MM-DD format", err)
+			return
+		}
+		updates["measured_at"] = measuredAt
+	}
+
+	if len(updates) == 0 {
+		utils.BadRequestError(c, "No fields to update", nil)
+		return
+	}
+
+	if err := ctrl.repo.UpdateBenchmark(benchmark, updates); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			utils.ConflictError(c, "Benchmark with this component, name and source already exists")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to update benchmark", err)
+		return
+	}
+
+	updated, err := ctrl.repo.GetBenchmarkByID(benchmark.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch benchmark", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Benchmark updated successfully", updated)
+}
+
+func (ctrl *BenchmarkController) DeleteBenchmark(c *gin.Context) {
+	benchmark, ok := ctrl.findBenchmark(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.repo.DeleteBenchmark(benchmark.ID); err != nil {
+		utils.InternalServerError(c, "Failed to delete benchmark", err)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// GetGames lists the games FPS can be estimated for
+func (ctrl *BenchmarkController) GetGames(c *gin.Context) {
+	games, err := ctrl.games.GetActiveGames(nil)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch games", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Games fetched successfully", games)
+}
+
+func (ctrl *BenchmarkController) findBenchmark(c *gin.Context) (*models.Benchmark, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.NotFoundError(c, "Benchmark not found")
+		return nil, false
+	}
+
+	benchmark, err := ctrl.repo.GetBenchmarkByID(uint(id))
+	if err != nil {
+		utils.NotFoundError(c, "Benchmark not found")
+		return nil, false
+	}
+
+	return benchmark, true
+}
@@ -0,0 +1,593 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"pc-builder/backend/services"
+	"pc-builder/backend/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BuildController struct {
+	repo      *repositories.BuildRepository
+	generator *services.BuildGeneratorService
+	exporter  *services.ExportService
+	importer  *services.ImportService
+	differ    *services.BuildDiffService
+	upgrades  *services.UpgradeService
+	perf      *services.PerformanceService
+	db        *gorm.DB
+}
+
+func NewBuildController(db *gorm.DB) *BuildController {
+	return &BuildController{
+		repo:      repositories.NewBuildRepository(db),
+		generator: services.NewBuildGeneratorService(db),
+		exporter:  services.NewExportService(db),
+		importer:  services.NewImportService(db),
+		differ:    services.NewBuildDiffService(db),
+		upgrades:  services.NewUpgradeService(db),
+		perf:      services.NewPerformanceService(db),
+		db:        db,
+	}
+}
+
+func (ctrl *BuildController) CreateBuild(c *gin.Context) {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return
+	}
+
+	var request struct {
+		Name       string                   `json:"name" binding:"required,max=255"`
+		Notes      string                   `json:"notes"`
+		Components []repositories.BuildSlot `json:"components" binding:"dive"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	slots := repositories.NormalizeBuildSlots(request.Components)
+	if !ctrl.validateSlots(c, slots) {
+		return
+	}
+
+	build := &models.Build{
+		UserID: userID,
+		Name:   request.Name,
+		Notes:  request.Notes,
+	}
+
+	if err := ctrl.repo.CreateBuild(build, slots); err != nil {
+		utils.InternalServerError(c, "Failed to create build", err)
+		return
+	}
+
+	created, err := ctrl.repo.GetBuildByID(build.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch build", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Build created successfully", created)
+}
+
+func (ctrl *BuildController) GetMyBuilds(c *gin.Context) {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return
+	}
+
+	var pagination repositories.PaginationParams
+	pagination.Page = 1
+	pagination.PageSize = 12
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		pagination.Page = page
+	}
+
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 && pageSize <= 100 {
+		pagination.PageSize = pageSize
+	}
+
+	response, err := ctrl.repo.GetBuildsByUser(userID, pagination)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch builds", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Builds fetched successfully", response)
+}
+
+func (ctrl *BuildController) GetBuildByID(c *gin.Context) {
+	build, ok := ctrl.findOwnedBuild(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, "Build fetched successfully", build)
+}
+
+func (ctrl *BuildController) UpdateBuild(c *gin.Context) {
+	build, ok := ctrl.findOwnedBuild(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Name       string                   `json:"name" binding:"max=255"`
+		Notes      *string                  `json:"notes"`
+		Components []repositories.BuildSlot `json:"components" binding:"omitempty,dive"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if request.Name != "" {
+		updates["name"] = request.Name
+	}
+	if request.Notes != nil {
+		updates["notes"] = *request.Notes
+	}
+
+	// A nil slice leaves the slots untouched, an empty one clears the build
+	var slots []repositories.BuildSlot
+	if request.Components != nil {
+		slots = repositories.NormalizeBuildSlots(request.Components)
+		if !ctrl.validateSlots(c, slots) {
+			return
+		}
+	}
+
+	if err := ctrl.repo.UpdateBuild(&build.Build, updates, slots); err != nil {
+		utils.InternalServerError(c, "Failed to update build", err)
+		return
+	}
+
+	updated, err := ctrl.repo.GetBuildByID(build.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch build", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Build updated successfully", updated)
+}
+
+func (ctrl *BuildController) DeleteBuild(c *gin.Context) {
+	build, ok := ctrl.findOwnedBuild(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.repo.DeleteBuild(build.ID); err != nil {
+		utils.InternalServerError(c, "Failed to delete build", err)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// ImportBuild matches a pasted parts list against the catalog and proposes a build. Nothing
+// is saved, the confirmed components are sent to CreateBuild.
+func (ctrl *BuildController) ImportBuild(c *gin.Context) {
+	var request struct {
+		Text string `json:"text" binding:"required,max=20000"`
+		Name string `json:"name" binding:"max=255"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	result, err := ctrl.importer.ParseImport(request.Text)
+	if err != nil {
+		if errors.Is(err, services.ErrEmptyImport) || errors.Is(err, services.ErrTooManyImportLines) {
+			utils.BadRequestError(c, err.Error(), err)
+			return
+		}
+		utils.InternalServerError(c, "Failed to import parts list", err)
+		return
+	}
+
+	if request.Name != "" {
+		result.Name = request.Name
+	}
+
+	utils.SuccessResponse(c, "Parts list imported successfully", result)
+}
+
+// ForkBuild copies a public build, or one of the caller's own, into the caller's account
+func (ctrl *BuildController) ForkBuild(c *gin.Context) {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return
+	}
+
+	source, ok := ctrl.findVisibleBuild(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional, it only overrides the name of the fork
+	var request struct {
+		Name string `json:"name" binding:"max=255"`
+	}
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			utils.BadRequestError(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	// Parts may have been deactivated, unpublished or trashed since the source was saved
+	slots := make([]repositories.BuildSlot, len(source.Components))
+	for i, slot := range source.Components {
+		slots[i] = repositories.BuildSlot{ComponentID: slot.ComponentID, Quantity: slot.Quantity}
+	}
+	if !ctrl.validateSlots(c, slots) {
+		return
+	}
+
+	fork, err := ctrl.repo.ForkBuild(&source.Build, userID, request.Name)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fork build", err)
+		return
+	}
+
+	created, err := ctrl.repo.GetBuildByID(fork.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch build", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Build forked successfully", created)
+}
+
+// ExportBuild renders the build as a parts list ready to paste in forums and chats
+func (ctrl *BuildController) ExportBuild(c *gin.Context) {
+	build, ok := ctrl.findVisibleBuild(c)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", services.ExportFormatMarkdown))
+
+	export, err := ctrl.exporter.PrepareExport(build, c.Query("currency"))
+	if err != nil {
+		utils.InternalServerError(c, "Failed to export build", err)
+		return
+	}
+
+	content, contentType, extension, err := services.RenderExport(export, format)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownExportFormat) {
+			utils.BadRequestError(c, "Format must be one of markdown, csv, bbcode, text", err)
+			return
+		}
+		utils.InternalServerError(c, "Failed to export build", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="build-%s.%s"`, build.ID, extension))
+	c.Data(http.StatusOK, contentType, []byte(content))
+}
+
+// DiffBuilds compares the build with the one given in the "with" query parameter
+func (ctrl *BuildController) DiffBuilds(c *gin.Context) {
+	if c.Query("with") == "" {
+		utils.BadRequestError(c, "The with query parameter is required", nil)
+		return
+	}
+
+	from, ok := ctrl.findVisibleBuild(c)
+	if !ok {
+		return
+	}
+
+	to, ok := ctrl.findVisibleBuildByID(c, c.Query("with"))
+	if !ok {
+		return
+	}
+
+	diff, err := ctrl.differ.Diff(from, to)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to compare builds", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Builds compared successfully", diff)
+}
+
+// SuggestUpgrades ranks the replacements that improve the build most within a budget
+func (ctrl *BuildController) SuggestUpgrades(c *gin.Context) {
+	build, ok := ctrl.findVisibleBuild(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Budget     models.PriceItem `json:"budget" binding:"required"`
+		Categories []string         `json:"categories" binding:"omitempty,dive,oneof=cpu gpu ram storage"`
+		Limit      int              `json:"limit" binding:"omitempty,min=1,max=10"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	if request.Budget.Currency == "" || request.Budget.Amount <= 0 {
+		utils.BadRequestError(c, "Budget requires a currency and a positive amount", nil)
+		return
+	}
+
+	advice, err := ctrl.upgrades.Advise(build, services.UpgradeOptions{
+		Budget:     request.Budget,
+		Categories: request.Categories,
+		Limit:      request.Limit,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrEmptyBuild) {
+			utils.BadRequestError(c, err.Error(), err)
+			return
+		}
+		utils.InternalServerError(c, "Failed to suggest upgrades", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Upgrades suggested successfully", advice)
+}
+
+// GetBuildPerformance estimates the FPS of the build CPU and GPU in a list of games
+func (ctrl *BuildController) GetBuildPerformance(c *gin.Context) {
+	build, ok := ctrl.findVisibleBuild(c)
+	if !ok {
+		return
+	}
+
+	resolution := strings.ToLower(c.DefaultQuery("resolution", services.DefaultResolution))
+	if _, exists := services.ResolutionFactors[resolution]; !exists {
+		utils.BadRequestError(c, "Resolution must be one of 1080p, 1440p, 4k", nil)
+		return
+	}
+
+	preset := strings.ToLower(c.DefaultQuery("preset", services.DefaultPreset))
+	if _, exists := services.PresetFactors[preset]; !exists {
+		utils.BadRequestError(c, "Preset must be one of low, medium, high, ultra", nil)
+		return
+	}
+
+	gameIDs := []string{}
+	for _, id := range strings.Split(c.Query("games"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			gameIDs = append(gameIDs, id)
+		}
+	}
+
+	report, err := ctrl.perf.Estimate(build, gameIDs, resolution, preset)
+	if err != nil {
+		if errors.Is(err, services.ErrMissingPerformanceParts) {
+			utils.HandleError(c, http.StatusUnprocessableEntity, err.Error(), err)
+			return
+		}
+		utils.InternalServerError(c, "Failed to estimate performance", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Performance estimated successfully", report)
+}
+
+// ShareBuild makes the build public and returns its share slug
+func (ctrl *BuildController) ShareBuild(c *gin.Context) {
+	build, ok := ctrl.findOwnedBuild(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.repo.ShareBuild(&build.Build); err != nil {
+		utils.InternalServerError(c, "Failed to share build", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Build shared successfully", gin.H{
+		"is_public":  build.IsPublic,
+		"share_slug": *build.ShareSlug,
+		"share_path": "/api/v1/shared/builds/" + *build.ShareSlug,
+	})
+}
+
+// RevokeShare makes the build private again, invalidating its share link
+func (ctrl *BuildController) RevokeShare(c *gin.Context) {
+	build, ok := ctrl.findOwnedBuild(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.repo.RevokeShare(&build.Build); err != nil {
+		utils.InternalServerError(c, "Failed to revoke share link", err)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// GetSharedBuild is the anonymous view of a public build
+func (ctrl *BuildController) GetSharedBuild(c *gin.Context) {
+	build, err := ctrl.repo.GetSharedBuild(c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.NotFoundError(c, "Shared build not found")
+			return
+		}
+		utils.InternalServerError(c, "Failed to fetch shared build", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Shared build fetched successfully", build)
+}
+
+// GenerateBuilds proposes complete builds for a budget without saving them
+func (ctrl *BuildController) GenerateBuilds(c *gin.Context) {
+	var request struct {
+		Budget     models.PriceItem   `json:"budget" binding:"required"`
+		Profile    string             `json:"profile" binding:"required,oneof=gaming workstation office"`
+		Allocation map[string]float64 `json:"allocation"`
+		MustHave   struct {
+			ComponentIDs []string                     `json:"component_ids"`
+			Specs        map[string]map[string]string `json:"specs"`
+		} `json:"must_have"`
+		Count int `json:"count" binding:"omitempty,min=1,max=3"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	if request.Budget.Currency == "" || request.Budget.Amount <= 0 {
+		utils.BadRequestError(c, "Budget requires a currency and a positive amount", nil)
+		return
+	}
+
+	builds, err := ctrl.generator.Generate(services.GenerateOptions{
+		Budget:       request.Budget,
+		Profile:      request.Profile,
+		Allocation:   request.Allocation,
+		ComponentIDs: request.MustHave.ComponentIDs,
+		Specs:        request.MustHave.Specs,
+		Count:        request.Count,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownProfile),
+			errors.Is(err, services.ErrUnknownCategory),
+			errors.Is(err, services.ErrInvalidComponents),
+			errors.Is(err, services.ErrMissingPrice),
+			errors.Is(err, services.ErrBudgetTooLow):
+			utils.BadRequestError(c, err.Error(), err)
+		case errors.Is(err, services.ErrNoCompatibleBuild):
+			utils.HandleError(c, http.StatusUnprocessableEntity, "No compatible build fits the budget", err)
+		default:
+			utils.InternalServerError(c, "Failed to generate builds", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, "Builds generated successfully", gin.H{
+		"total":  len(builds),
+		"builds": builds,
+	})
+}
+
+// findOwnedBuild loads the build from the :id param and writes the error response when
+// it does not exist or belongs to another user
+func (ctrl *BuildController) findOwnedBuild(c *gin.Context) (*models.BuildWithTotals, bool) {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.NotFoundError(c, "Build not found")
+		return nil, false
+	}
+
+	build, err := ctrl.repo.GetBuildByID(id)
+	if err != nil || build.UserID != userID {
+		utils.NotFoundError(c, "Build not found")
+		return nil, false
+	}
+
+	return build, true
+}
+
+// findVisibleBuild loads the build from the :id param when it is public or owned by the
+// caller, and writes a not found response otherwise
+func (ctrl *BuildController) findVisibleBuild(c *gin.Context) (*models.BuildWithTotals, bool) {
+	return ctrl.findVisibleBuildByID(c, c.Param("id"))
+}
+
+func (ctrl *BuildController) findVisibleBuildByID(c *gin.Context, rawID string) (*models.BuildWithTotals, bool) {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return nil, false
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		utils.NotFoundError(c, "Build not found")
+		return nil, false
+	}
+
+	build, err := ctrl.repo.GetBuildByID(id)
+	if err != nil || (build.UserID != userID && !build.IsPublic) {
+		utils.NotFoundError(c, "Build not found")
+		return nil, false
+	}
+
+	return build, true
+}
+
+func (ctrl *BuildController) validateSlots(c *gin.Context, slots []repositories.BuildSlot) bool {
+	if len(slots) == 0 {
+		return true
+	}
+
+	componentIDs := make([]string, len(slots))
+	for i, slot := range slots {
+		componentIDs[i] = slot.ComponentID
+	}
+
+	missing, err := ctrl.repo.FindMissingComponents(componentIDs)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to validate components", err)
+		return false
+	}
+
+	if len(missing) > 0 {
+		utils.BadRequestError(c, fmt.Sprintf("Invalid component ID: %s", strings.Join(missing, ", ")), nil)
+		return false
+	}
+
+	return true
+}
+
+func getCurrentUserID(c *gin.Context) (uuid.UUID, bool) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	userID, ok := value.(uuid.UUID)
+	return userID, ok
+}
+
+// currentUserRef returns the authenticated user for audit columns, nil when there is none
+func currentUserRef(c *gin.Context) *uuid.UUID {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		return nil
+	}
+	return &userID
+}
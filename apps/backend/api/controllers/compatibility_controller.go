@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"fmt"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"pc-builder/backend/services"
+	"pc-builder/backend/utils"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CompatibilityController struct {
+	service *services.CompatibilityService
+	rules   *repositories.CompatibilityRuleRepository
+	db      *gorm.DB
+}
+
+func NewCompatibilityController(db *gorm.DB) *CompatibilityController {
+	return &CompatibilityController{
+		service: services.NewCompatibilityService(db),
+		rules:   repositories.NewCompatibilityRuleRepository(db),
+		db:      db,
+	}
+}
+
+// CompatibilityRequest accepts either plain component IDs or slots with quantities
+type CompatibilityRequest struct {
+	ComponentIDs []string                 `json:"component_ids"`
+	Components   []repositories.BuildSlot `json:"components" binding:"omitempty,dive"`
+}
+
+func (request CompatibilityRequest) Slots() []repositories.BuildSlot {
+	slots := append([]repositories.BuildSlot{}, request.Components...)
+	for _, id := range request.ComponentIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			slots = append(slots, repositories.BuildSlot{ComponentID: id, Quantity: 1})
+		}
+	}
+	return slots
+}
+
+func (ctrl *CompatibilityController) CheckCompatibility(c *gin.Context) {
+	var request CompatibilityRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	slots := request.Slots()
+	if len(slots) == 0 {
+		utils.BadRequestError(c, "At least one component is required", nil)
+		return
+	}
+
+	report, missing, err := ctrl.service.Check(slots)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to check compatibility", err)
+		return
+	}
+
+	if len(missing) > 0 {
+		utils.BadRequestError(c, fmt.Sprintf("Invalid component ID: %s", strings.Join(missing, ", ")), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, "Compatibility checked successfully", report)
+}
+
+func (ctrl *CompatibilityController) EstimatePower(c *gin.Context) {
+	var request struct {
+		CompatibilityRequest
+		HeadroomPercent *float64 `json:"headroom_percent" binding:"omitempty,min=0,max=100"` // Nil uses the default, 0 is kept
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	slots := request.Slots()
+	if len(slots) == 0 {
+		utils.BadRequestError(c, "At least one component is required", nil)
+		return
+	}
+
+	parts, missing, err := ctrl.service.LoadParts(slots)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to estimate power", err)
+		return
+	}
+
+	if len(missing) > 0 {
+		utils.BadRequestError(c, fmt.Sprintf("Invalid component ID: %s", strings.Join(missing, ", ")), nil)
+		return
+	}
+
+	headroomPercent := services.DefaultHeadroomPercent
+	if request.HeadroomPercent != nil {
+		headroomPercent = *request.HeadroomPercent
+	}
+
+	utils.SuccessResponse(c, "Power estimated successfully", services.EstimatePower(parts, headroomPercent))
+}
+
+func (ctrl *CompatibilityController) GetCompatibilityRules(c *gin.Context) {
+	rules, err := ctrl.rules.GetAllRules()
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch compatibility rules", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Compatibility rules fetched successfully", rules)
+}
+
+func (ctrl *CompatibilityController) CreateCompatibilityRule(c *gin.Context) {
+	var request struct {
+		ID               string `json:"id" binding:"required,max=100"`
+		Name             string `json:"name" binding:"required"`
+		SourceCategoryID string `json:"source_category_id" binding:"required"`
+		SourceSpecKey    string `json:"source_spec_key" binding:"required"`
+		Operator         string `json:"operator" binding:"required"`
+		TargetCategoryID string `json:"target_category_id" binding:"required"`
+		TargetSpecKey    string `json:"target_spec_key" binding:"required"`
+		Severity         string `json:"severity"`
+		Message          string `json:"message" binding:"required"`
+		IsActive         *bool  `json:"is_active"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	if request.Severity == "" {
+		request.Severity = models.RuleSeverityError
+	}
+
+	rule := &models.CompatibilityRule{
+		ID:               request.ID,
+		Name:             request.Name,
+		SourceCategoryID: request.SourceCategoryID,
+		SourceSpecKey:    request.SourceSpecKey,
+		Operator:         request.Operator,
+		TargetCategoryID: request.TargetCategoryID,
+		TargetSpecKey:    request.TargetSpecKey,
+		Severity:         request.Severity,
+		Message:          request.Message,
+		IsActive:         true,
+	}
+
+	if !ctrl.validateRule(c, rule) {
+		return
+	}
+
+	if err := ctrl.rules.CreateRule(rule); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			utils.ConflictError(c, "Compatibility rule with this ID already exists")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to create compatibility rule", err)
+		return
+	}
+
+	// is_active defaults to true in the database, so false has to be written explicitly
+	if request.IsActive != nil && !*request.IsActive {
+		if err := ctrl.rules.UpdateRule(rule, map[string]interface{}{"is_active": false}); err != nil {
+			utils.InternalServerError(c, "Failed to create compatibility rule", err)
+			return
+		}
+		rule.IsActive = false
+	}
+
+	utils.CreatedResponse(c, "Compatibility rule created successfully", rule)
+}
+
+func (ctrl *CompatibilityController) UpdateCompatibilityRule(c *gin.Context) {
+	id := c.Param("id")
+
+	var request struct {
+		Name             string `json:"name"`
+		SourceCategoryID string `json:"source_category_id"`
+		SourceSpecKey    string `json:"source_spec_key"`
+		Operator         string `json:"operator"`
+		TargetCategoryID string `json:"target_category_id"`
+		TargetSpecKey    string `json:"target_spec_key"`
+		Severity         string `json:"severity"`
+		Message          string `json:"message"`
+		IsActive         *bool  `json:"is_active"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	rule, err := ctrl.rules.GetRuleByID(id)
+	if err != nil {
+		utils.NotFoundError(c, "Compatibility rule not found")
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if request.Name != "" {
+		updates["name"] = request.Name
+		rule.Name = request.Name
+	}
+	if request.SourceCategoryID != "" {
+		updates["source_category_id"] = request.SourceCategoryID
+		rule.SourceCategoryID = request.SourceCategoryID
+	}
+	if request.SourceSpecKey != "" {
+		updates["source_spec_key"] = request.SourceSpecKey
+		rule.SourceSpecKey = request.SourceSpecKey
+	}
+	if request.Operator != "" {
+		updates["operator"] = request.Operator
+		rule.Operator = request.Operator
+	}
+	if request.TargetCategoryID != "" {
+		updates["target_category_id"] = request.TargetCategoryID
+		rule.TargetCategoryID = request.TargetCategoryID
+	}
+	if request.TargetSpecKey != "" {
+		updates["target_spec_key"] = request.TargetSpecKey
+		rule.TargetSpecKey = request.TargetSpecKey
+	}
+	if request.Severity != "" {
+		updates["severity"] = request.Severity
+		rule.Severity = request.Severity
+	}
+	if request.Message != "" {
+		updates["message"] = request.Message
+		rule.Message = request.Message
+	}
+	if request.IsActive != nil {
+		updates["is_active"] = *request.IsActive
+		rule.IsActive = *request.IsActive
+	}
+
+	if !ctrl.validateRule(c, rule) {
+		return
+	}
+
+	if len(updates) > 0 {
+		if err := ctrl.rules.UpdateRule(rule, updates); err != nil {
+			utils.InternalServerError(c, "Failed to update compatibility rule", err)
+			return
+		}
+	}
+
+	utils.SuccessResponse(c, "Compatibility rule updated successfully", rule)
+}
+
+func (ctrl *CompatibilityController) DeleteCompatibilityRule(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := ctrl.rules.GetRuleByID(id); err != nil {
+		utils.NotFoundError(c, "Compatibility rule not found")
+		return
+	}
+
+	if err := ctrl.rules.DeleteRule(id); err != nil {
+		utils.InternalServerError(c, "Failed to delete compatibility rule", err)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+func (ctrl *CompatibilityController) validateRule(c *gin.Context, rule *models.CompatibilityRule) bool {
+	if !slices.Contains(models.RuleOperators, rule.Operator) {
+		utils.BadRequestError(c, fmt.Sprintf("Invalid operator, expected one of: %s", strings.Join(models.RuleOperators, ", ")), nil)
+		return false
+	}
+
+	if !slices.Contains(models.RuleSeverities, rule.Severity) {
+		utils.BadRequestError(c, fmt.Sprintf("Invalid severity, expected one of: %s", strings.Join(models.RuleSeverities, ", ")), nil)
+		return false
+	}
+
+	for _, categoryID := range []string{rule.SourceCategoryID, rule.TargetCategoryID} {
+		var category models.Category
+		if err := ctrl.db.First(&category, "id = ?", categoryID).Error; err != nil {
+			utils.BadRequestError(c, fmt.Sprintf("Invalid category ID: %s", categoryID), err)
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"pc-builder/backend/utils"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SubmitComponent sends a draft or rejected component of the vendor to the review queue
+func (ctrl *ComponentController) SubmitComponent(c *gin.Context) {
+	var component models.Component
+	if err := ctrl.db.Where("id = ?", c.Param("id")).First(&component).Error; err != nil {
+		utils.NotFoundError(c, "Component not found")
+		return
+	}
+
+	if !canMutateComponent(c, &component) {
+		return
+	}
+
+	if component.Status != models.ComponentStatusDraft && component.Status != models.ComponentStatusRejected {
+		utils.ConflictError(c, "Only draft or rejected components can be submitted, this one is "+component.Status)
+		return
+	}
+
+	if err := ctrl.reviews.SubmitForReview(&component); err != nil {
+		utils.InternalServerError(c, "Failed to submit component", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Component submitted for review", gin.H{
+		"id":     component.ID,
+		"status": models.ComponentStatusPendingReview,
+	})
+}
+
+// GetReviewQueue lists the components waiting to be published and the published ones with
+// pending vendor changes, longest waiting first
+func (ctrl *ComponentController) GetReviewQueue(c *gin.Context) {
+	var pagination repositories.PaginationParams
+	pagination.Page = 1
+	pagination.PageSize = 20
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		pagination.Page = page
+	}
+
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 && pageSize <= 100 {
+		pagination.PageSize = pageSize
+	}
+
+	response, err := ctrl.reviews.GetReviewQueue(pagination)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch review queue", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Review queue fetched successfully", response)
+}
+
+func (ctrl *ComponentController) ApproveComponent(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := ctrl.reviews.Approve(id, currentUserRef(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.NotFoundError(c, "Component not found")
+			return
+		}
+
+		if errors.Is(err, repositories.ErrNothingToReview) {
+			utils.ConflictError(c, "Component has nothing to review")
+			return
+		}
+
+		if strings.Contains(err.Error(), "foreign key") {
+			utils.ConflictError(c, "The changes reference a category or brand that no longer exists")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to approve component", err)
+		return
+	}
+
+	if result.Applied != nil && !reflect.DeepEqual(result.Previous, result.Applied.Price) {
+		err = ctrl.alerts.CheckPriceChange(id, result.Applied.Name, result.Previous, result.Applied.Price)
+		if err != nil {
+			log.Printf("⚠️ Failed to process price alerts for component %s: %v", id, err)
+		}
+	}
+
+	message := "Component published successfully"
+	if !result.Published {
+		message = "Component changes approved successfully"
+	}
+
+	utils.SuccessResponse(c, message, gin.H{
+		"id":     id,
+		"status": models.ComponentStatusPublished,
+	})
+}
+
+func (ctrl *ComponentController) RejectComponent(c *gin.Context) {
+	id := c.Param("id")
+
+	var request struct {
+		Reason string `json:"reason" binding:"required,max=1000"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		utils.BadRequestError(c, "A reason is required", nil)
+		return
+	}
+
+	if err := ctrl.reviews.Reject(id, currentUserRef(c), reason); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.NotFoundError(c, "Component not found")
+			return
+		}
+
+		if errors.Is(err, repositories.ErrNothingToReview) {
+			utils.ConflictError(c, "Component has nothing to review")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to reject component", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Component rejected successfully", gin.H{
+		"id":          id,
+		"review_note": reason,
+	})
+}
+
+// pendingChanges are the changes of the component already waiting for review, none when
+// nothing is staged
+func pendingChanges(component *models.Component) (models.ComponentChanges, error) {
+	var changes models.ComponentChanges
+	if len(component.PendingChanges) == 0 {
+		return changes, nil
+	}
+
+	err := json.Unmarshal(component.PendingChanges, &changes)
+	return changes, err
+}
+
+// initialComponentStatus publishes components created by admins right away, vendor ones
+// start as drafts
+func initialComponentStatus(c *gin.Context) string {
+	if c.GetString("user_role") == models.RoleVendor {
+		return models.ComponentStatusDraft
+	}
+	return models.ComponentStatusPublished
+}
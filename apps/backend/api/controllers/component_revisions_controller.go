@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"pc-builder/backend/utils"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetComponentRevisions lists the revisions of a component, newest first
+func (ctrl *ComponentController) GetComponentRevisions(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, ok := ctrl.findRevisionedComponent(c, id); !ok {
+		return
+	}
+
+	var pagination repositories.PaginationParams
+	pagination.Page = 1
+	pagination.PageSize = 20
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		pagination.Page = page
+	}
+
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 && pageSize <= 100 {
+		pagination.PageSize = pageSize
+	}
+
+	response, err := ctrl.revisions.GetRevisions(id, pagination)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch revisions", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Revisions fetched successfully", response)
+}
+
+// DiffComponentRevisions lists the fields changed from the ?from revision to the ?to revision
+func (ctrl *ComponentController) DiffComponentRevisions(c *gin.Context) {
+	id := c.Param("id")
+
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil || from < 1 || to < 1 {
+		utils.BadRequestError(c, "from and to must be revision numbers", nil)
+		return
+	}
+
+	if _, ok := ctrl.findRevisionedComponent(c, id); !ok {
+		return
+	}
+
+	fromSnapshot, ok := ctrl.findRevisionSnapshot(c, id, from)
+	if !ok {
+		return
+	}
+	toSnapshot, ok := ctrl.findRevisionSnapshot(c, id, to)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, "Revisions compared successfully", gin.H{
+		"component_id": id,
+		"from":         from,
+		"to":           to,
+		"changes":      models.DiffSnapshots(fromSnapshot, toSnapshot),
+	})
+}
+
+// RollbackComponent restores a component to the :revision param, which is written as a
+// new revision
+func (ctrl *ComponentController) RollbackComponent(c *gin.Context) {
+	id := c.Param("id")
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		utils.NotFoundError(c, "Revision not found")
+		return
+	}
+
+	component, ok := ctrl.findRevisionedComponent(c, id)
+	if !ok {
+		return
+	}
+
+	previous, restored, err := ctrl.revisions.RollbackComponent(id, revision, currentUserRef(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.NotFoundError(c, "Revision not found")
+			return
+		}
+
+		if strings.Contains(err.Error(), "foreign key") {
+			utils.ConflictError(c, "The revision references a category or brand that no longer exists")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to roll back component", err)
+		return
+	}
+
+	// Drafts and components waiting for review are not public, nobody is watching their price
+	if component.Status == models.ComponentStatusPublished && !reflect.DeepEqual(previous, restored.Price) {
+		err = ctrl.alerts.CheckPriceChange(id, restored.Name, previous, restored.Price)
+		if err != nil {
+			log.Printf("⚠️ Failed to process price alerts for component %s: %v", id, err)
+		}
+	}
+
+	utils.SuccessResponse(c, "Component rolled back successfully", gin.H{
+		"component_id": id,
+		"revision":     revision,
+		"snapshot":     restored,
+	})
+}
+
+// findRevisionedComponent loads the component, inactive ones included, and writes a not
+// found response when it does not exist
+func (ctrl *ComponentController) findRevisionedComponent(c *gin.Context, id string) (models.Component, bool) {
+	var component models.Component
+	if err := ctrl.db.Where("id = ?", id).First(&component).Error; err != nil {
+		utils.NotFoundError(c, "Component not found")
+		return component, false
+	}
+	return component, true
+}
+
+func (ctrl *ComponentController) findRevisionSnapshot(c *gin.Context, id string, revision int) (models.ComponentSnapshot, bool) {
+	componentRevision, err := ctrl.revisions.GetRevision(id, revision)
+	if err != nil {
+		utils.NotFoundError(c, "Revision "+strconv.Itoa(revision)+" not found")
+		return models.ComponentSnapshot{}, false
+	}
+
+	snapshot, err := componentRevision.ParseSnapshot()
+	if err != nil {
+		utils.InternalServerError(c, "Failed to read revision", err)
+		return models.ComponentSnapshot{}, false
+	}
+	return snapshot, true
+}
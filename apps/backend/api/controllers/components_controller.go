@@ -2,28 +2,49 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"pc-builder/backend/api/models"
 	"pc-builder/backend/api/repositories"
+	"pc-builder/backend/services"
 	"pc-builder/backend/utils"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type ComponentController struct {
-	repo *repositories.ComponentRepository
-	db   *gorm.DB
+	repo          *repositories.ComponentRepository
+	builds        *repositories.BuildRepository
+	compatibility *services.CompatibilityService
+	comparison    *services.ComparisonService
+	priceHistory  *repositories.PriceHistoryRepository
+	alerts        *services.PriceAlertService
+	trash         *repositories.TrashRepository
+	revisions     *repositories.ComponentRevisionRepository
+	reviews       *repositories.ComponentReviewRepository
+	db            *gorm.DB
 }
 
-func NewComponentController(db *gorm.DB) *ComponentController {
+func NewComponentController(db *gorm.DB, alerts *services.PriceAlertService) *ComponentController {
 	return &ComponentController{
-		repo: repositories.NewComponentRepository(db),
-		db:   db,
+		repo:          repositories.NewComponentRepository(db),
+		builds:        repositories.NewBuildRepository(db),
+		compatibility: services.NewCompatibilityService(db),
+		comparison:    services.NewComparisonService(db),
+		priceHistory:  repositories.NewPriceHistoryRepository(db),
+		alerts:        alerts,
+		trash:         repositories.NewTrashRepository(db),
+		revisions:     repositories.NewComponentRevisionRepository(db),
+		reviews:       repositories.NewComponentReviewRepository(db),
+		db:            db,
 	}
 }
 
@@ -68,7 +89,8 @@ func (ctrl *ComponentController) GetComponentsWithPagination(c *gin.Context) {
 	filters.Search = c.Query("search")
 	filters.SortBy = c.Query("sort_by")
 	filters.SortOrder = c.Query("sort_order")
-	filters.Currency = c.Query("currency")
+	filters.Currency = strings.ToUpper(strings.TrimSpace(c.Query("currency")))
+	filters.Benchmark = c.Query("benchmark")
 
 	// Parse spec filters
 	filters.Specs = make(map[string]string)
@@ -79,6 +101,32 @@ func (ctrl *ComponentController) GetComponentsWithPagination(c *gin.Context) {
 		}
 	}
 
+	// Only keep components compatible with a saved build or a list of component IDs
+	if compatibleWith := strings.TrimSpace(c.Query("compatible_with")); compatibleWith != "" {
+		filters.CompatibleWith = compatibleWith
+
+		if len(filters.CategoryIDs) == 0 {
+			utils.BadRequestError(c, "compatible_with requires category_id", nil)
+			return
+		}
+
+		parts, message, err := ctrl.resolveCompatibleWith(c, compatibleWith)
+		if err != nil {
+			utils.InternalServerError(c, "Failed to resolve compatible_with", err)
+			return
+		}
+		if message != "" {
+			utils.BadRequestError(c, message, nil)
+			return
+		}
+
+		filters.ExcludedIDs, err = ctrl.compatibility.IncompatibleComponentIDs(parts, filters)
+		if err != nil {
+			utils.InternalServerError(c, "Failed to check compatibility", err)
+			return
+		}
+	}
+
 	// Set default values
 	if filters.SortBy == "" {
 		filters.SortBy = "created_at"
@@ -96,6 +144,44 @@ func (ctrl *ComponentController) GetComponentsWithPagination(c *gin.Context) {
 	utils.SuccessResponse(c, "Components fetched successfully", response)
 }
 
+// resolveCompatibleWith loads the parts of a build ID or of comma-separated component IDs.
+// Like findVisibleBuild, a build has to be public or owned by the caller. A non-empty
+// message describes invalid input.
+func (ctrl *ComponentController) resolveCompatibleWith(c *gin.Context, value string) ([]services.BuildPart, string, error) {
+	var slots []repositories.BuildSlot
+
+	if buildID, err := uuid.Parse(value); err == nil {
+		build, err := ctrl.builds.GetBuildByID(buildID)
+		if err != nil {
+			return nil, "Build not found", nil
+		}
+
+		if userID, ok := getCurrentUserID(c); !build.IsPublic && (!ok || build.UserID != userID) {
+			return nil, "Build not found", nil
+		}
+
+		for _, slot := range build.Components {
+			slots = append(slots, repositories.BuildSlot{ComponentID: slot.ComponentID, Quantity: slot.Quantity})
+		}
+	} else {
+		for _, id := range strings.Split(value, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				slots = append(slots, repositories.BuildSlot{ComponentID: id, Quantity: 1})
+			}
+		}
+	}
+
+	parts, missing, err := ctrl.compatibility.LoadParts(slots)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Sprintf("Invalid component ID: %s", strings.Join(missing, ", ")), nil
+	}
+
+	return parts, "", nil
+}
+
 func (ctrl *ComponentController) CreateComponent(c *gin.Context) {
 	var request struct {
 		ID         string                          `json:"id" binding:"required"`
@@ -113,6 +199,9 @@ func (ctrl *ComponentController) CreateComponent(c *gin.Context) {
 		return
 	}
 
+	// Derived prices are recomputed on read and never stored
+	request.Price = request.Price.Stored()
+
 	var category models.Category
 
 	if err := ctrl.db.First(&category, "id = ?", request.CategoryID).Error; err != nil {
@@ -149,7 +238,9 @@ func (ctrl *ComponentController) CreateComponent(c *gin.Context) {
 		Price:      priceJSON,
 		ImageURL:   imageJSON,
 		IsActive:   true,
+		CreatedBy:  currentUserRef(c),
 	}
+	component.Status = initialComponentStatus(c)
 
 	// Convert specs to string map
 	specsMap := make(map[string]string)
@@ -161,7 +252,13 @@ func (ctrl *ComponentController) CreateComponent(c *gin.Context) {
 		}
 	}
 
-	err = ctrl.repo.CreateComponent(component, request.BrandIDs, specsMap)
+	specsMap, err = repositories.NormalizeSpecs(specsMap)
+	if err != nil {
+		utils.BadRequestError(c, "Invalid specs", err)
+		return
+	}
+
+	err = ctrl.repo.CreateComponent(component, request.BrandIDs, specsMap, currentUserRef(c))
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
 			utils.ConflictError(c, "Component with this ID already exists")
@@ -187,6 +284,86 @@ func (ctrl *ComponentController) GetComponentByID(c *gin.Context) {
 	utils.SuccessResponse(c, "Component fetched successfully", component)
 }
 
+// GetPriceHistory returns the price time series of a component per currency. from and to
+// take a date or an RFC 3339 timestamp, a date bound covers the whole day.
+func (ctrl *ComponentController) GetPriceHistory(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := ctrl.repo.GetComponentByID(id); err != nil {
+		utils.NotFoundError(c, "Component not found")
+		return
+	}
+
+	filter := repositories.PriceHistoryFilter{Currency: strings.ToUpper(strings.TrimSpace(c.Query("currency")))}
+
+	var err error
+	if filter.From, err = parseHistoryBound(c.Query("from"), false); err != nil {
+		utils.BadRequestError(c, "from must be a YYYY-MM-DD date or an RFC 3339 timestamp", err)
+		return
+	}
+	if filter.To, err = parseHistoryBound(c.Query("to"), true); err != nil {
+		utils.BadRequestError(c, "to must be a YYYY-MM-DD date or an RFC 3339 timestamp", err)
+		return
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		utils.BadRequestError(c, "from must be before to", nil)
+		return
+	}
+
+	histories, err := ctrl.priceHistory.GetPriceHistory(id, filter)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch price history", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Price history fetched successfully", gin.H{
+		"component_id": id,
+		"currencies":   histories,
+	})
+}
+
+// parseHistoryBound reads a range bound, an end date is moved to the start of the next day
+// so the bound stays exclusive
+func parseHistoryBound(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		if end {
+			date = date.AddDate(0, 0, 1)
+		}
+		return date, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+// CompareComponents aligns the specs of several components side by side
+func (ctrl *ComponentController) CompareComponents(c *gin.Context) {
+	ids := []string{}
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	comparison, err := ctrl.comparison.Compare(ids, c.Query("currency"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrComparisonSize):
+			utils.BadRequestError(c, err.Error(), err)
+		case errors.Is(err, services.ErrComparedNotFound):
+			utils.NotFoundError(c, err.Error())
+		default:
+			utils.InternalServerError(c, "Failed to compare components", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, "Components compared successfully", comparison)
+}
+
 func (ctrl *ComponentController) UpdateComponent(c *gin.Context) {
 	id := c.Param("id")
 
@@ -207,6 +384,8 @@ func (ctrl *ComponentController) UpdateComponent(c *gin.Context) {
 		return
 	}
 
+	request.Price = request.Price.Stored()
+
 	// Check if component exists
 	var existingComponent models.Component
 	err := ctrl.db.Where("id = ?", id).First(&existingComponent).Error
@@ -215,9 +394,94 @@ func (ctrl *ComponentController) UpdateComponent(c *gin.Context) {
 		return
 	}
 
+	if !canMutateComponent(c, &existingComponent) {
+		return
+	}
+
+	if request.CategoryID != "" {
+		var category models.Category
+		if err := ctrl.db.First(&category, "id = ?", request.CategoryID).Error; err != nil {
+			utils.BadRequestError(c, "Invalid category ID", err)
+			return
+		}
+	}
+
+	for _, brandAssoc := range request.BrandIDs {
+		var brand models.Brand
+		if err := ctrl.db.First(&brand, "id = ?", brandAssoc.BrandID).Error; err != nil {
+			utils.BadRequestError(c, fmt.Sprintf("Invalid brand ID: %s", brandAssoc.BrandID), err)
+			return
+		}
+	}
+
+	specsMap := make(map[string]string)
+	for key, value := range request.Specs {
+		specsMap[key] = fmt.Sprintf("%v", value)
+	}
+
+	specsMap, err = repositories.NormalizeSpecs(specsMap)
+	if err != nil {
+		utils.BadRequestError(c, "Invalid specs", err)
+		return
+	}
+
+	// Vendor changes to a published component wait for an admin review, the live component
+	// is left as is until then
+	if c.GetString("user_role") == models.RoleVendor && existingComponent.Status == models.ComponentStatusPublished {
+		changes, err := pendingChanges(&existingComponent)
+		if err != nil {
+			utils.InternalServerError(c, "Failed to load component", err)
+			return
+		}
+
+		var staged models.ComponentChanges
+		if request.Name != "" {
+			staged.Name = &request.Name
+		}
+		if request.CategoryID != "" {
+			staged.CategoryID = &request.CategoryID
+		}
+		if request.Models != "" {
+			staged.Models = &request.Models
+		}
+		staged.IsActive = request.IsActive
+		staged.InStock = request.InStock
+		if len(request.Price) > 0 {
+			staged.Price = request.Price
+		}
+		if len(request.ImageURL) > 0 {
+			staged.ImageURL = request.ImageURL
+		}
+		if len(request.BrandIDs) > 0 {
+			staged.Brands = make([]models.SnapshotBrand, 0, len(request.BrandIDs))
+			for _, brandAssoc := range request.BrandIDs {
+				staged.Brands = append(staged.Brands, models.SnapshotBrand{BrandID: brandAssoc.BrandID, IsPrimary: brandAssoc.IsPrimary})
+			}
+		}
+		if len(specsMap) > 0 {
+			staged.Specs = specsMap
+		}
+		changes = changes.Merge(staged)
+
+		if err := ctrl.reviews.StageChanges(&existingComponent, changes); err != nil {
+			utils.InternalServerError(c, "Failed to submit changes", err)
+			return
+		}
+
+		utils.SuccessResponse(c, "Changes submitted for review", changes)
+		return
+	}
+
 	err = ctrl.db.Transaction(func(tx *gorm.DB) error {
 		updates := make(map[string]interface{})
 
+		// Editing a rejected component turns it back into a draft to be submitted again, and so
+		// does a vendor editing one waiting for review, which takes it out of the review queue
+		if existingComponent.Status == models.ComponentStatusRejected ||
+			(existingComponent.Status == models.ComponentStatusPendingReview && c.GetString("user_role") == models.RoleVendor) {
+			updates["status"] = models.ComponentStatusDraft
+		}
+
 		if request.Name != "" {
 			updates["name"] = request.Name
 		}
@@ -240,6 +504,11 @@ func (ctrl *ComponentController) UpdateComponent(c *gin.Context) {
 				return err
 			}
 			updates["price"] = priceJSON
+
+			err = repositories.RecordPriceChanges(tx, id, models.ParsePrice(existingComponent.Price), request.Price, currentUserRef(c))
+			if err != nil {
+				return err
+			}
 		}
 
 		if len(request.ImageURL) > 0 {
@@ -280,7 +549,7 @@ func (ctrl *ComponentController) UpdateComponent(c *gin.Context) {
 		}
 
 		// Update specs if provided
-		if len(request.Specs) > 0 {
+		if len(specsMap) > 0 {
 			// Delete existing specs
 			err = tx.Where("component_id = ?", id).Delete(&models.ComponentSpec{}).Error
 			if err != nil {
@@ -288,12 +557,12 @@ func (ctrl *ComponentController) UpdateComponent(c *gin.Context) {
 			}
 
 			// Create new specs
-			for key, value := range request.Specs {
+			for key, value := range specsMap {
 				spec := models.ComponentSpec{
 					ComponentID:  id,
 					SpecKey:      key,
-					SpecValue:    fmt.Sprintf("%v", value),
-					SpecType:     "string",
+					SpecValue:    value,
+					SpecType:     repositories.GetSpecType(key),
 					IsFilterable: isFilterableSpec(key),
 				}
 				err = tx.Create(&spec).Error
@@ -303,7 +572,7 @@ func (ctrl *ComponentController) UpdateComponent(c *gin.Context) {
 			}
 		}
 
-		return nil
+		return repositories.RecordRevision(tx, id, models.RevisionActionUpdate, currentUserRef(c))
 	})
 
 	if err != nil {
@@ -311,22 +580,37 @@ func (ctrl *ComponentController) UpdateComponent(c *gin.Context) {
 		return
 	}
 
+	// Only published components are public, so only their price changes alert anyone
+	if len(request.Price) > 0 && existingComponent.Status == models.ComponentStatusPublished {
+		name := existingComponent.Name
+		if request.Name != "" {
+			name = request.Name
+		}
+
+		err = ctrl.alerts.CheckPriceChange(id, name, models.ParsePrice(existingComponent.Price), request.Price)
+		if err != nil {
+			log.Printf("⚠️ Failed to process price alerts for component %s: %v", id, err)
+		}
+	}
+
 	utils.SuccessResponse(c, "Component updated successfully", nil)
 }
 
-// DeleteComponent soft deletes a component
+// DeleteComponent moves a component to the trash, admins can restore or purge it from there
 func (ctrl *ComponentController) DeleteComponent(c *gin.Context) {
 	id := c.Param("id")
 
-	// Soft delete by setting is_active to false
-	err := ctrl.db.Delete(models.ComponentSpec{}, "component_id = ?", id).Error
-	if err != nil {
-		utils.InternalServerError(c, "Failed to delete component specs", err)
+	var component models.Component
+	if err := ctrl.db.Where("id = ?", id).First(&component).Error; err != nil {
+		utils.NotFoundError(c, "Component not found")
 		return
 	}
 
-	err = ctrl.db.Delete(models.Component{}, "id = ?", id).Error
-	if err != nil {
+	if !canMutateComponent(c, &component) {
+		return
+	}
+
+	if err := ctrl.trash.MoveToTrash(repositories.TrashComponents, id, currentUserRef(c)); err != nil {
 		utils.InternalServerError(c, "Failed to delete component", err)
 		return
 	}
@@ -376,7 +660,7 @@ func (ctrl *ComponentController) BulkCreateComponents(c *gin.Context) {
 		}
 
 		// Convert to component model
-		priceJSON, err := json.Marshal(compReq.Price)
+		priceJSON, err := json.Marshal(compReq.Price.Stored())
 		if err != nil {
 			result.Error = "Invalid price format"
 			result.Message = "Failed to process component"
@@ -402,7 +686,9 @@ func (ctrl *ComponentController) BulkCreateComponents(c *gin.Context) {
 			Price:      priceJSON,
 			ImageURL:   imageJSON,
 			IsActive:   true,
+			CreatedBy:  currentUserRef(c),
 		}
+		component.Status = initialComponentStatus(c)
 
 		// Convert specs
 		specsMap := make(map[string]string)
@@ -410,8 +696,17 @@ func (ctrl *ComponentController) BulkCreateComponents(c *gin.Context) {
 			specsMap[key] = fmt.Sprintf("%v", value)
 		}
 
+		specsMap, err = repositories.NormalizeSpecs(specsMap)
+		if err != nil {
+			result.Error = err.Error()
+			result.Message = "Invalid specs"
+			results = append(results, result)
+			totalFailed++
+			continue
+		}
+
 		// Create component
-		err = ctrl.repo.CreateComponent(component, compReq.BrandIDs, specsMap)
+		err = ctrl.repo.CreateComponent(component, compReq.BrandIDs, specsMap, currentUserRef(c))
 		if err != nil {
 			if strings.Contains(err.Error(), "duplicate key") {
 				result.Error = "Component with this ID already exists"
@@ -453,8 +748,8 @@ func (ctrl *ComponentController) GetAllComponents(c *gin.Context) {
 	var components []models.Component
 
 	err := ctrl.db.
-		Select("*").
-		Where("is_active = true").
+		Omit("review_note", "reviewed_by", "pending_changes").
+		Where("is_active = true AND status = ?", models.ComponentStatusPublished).
 		Find(&components).Error
 
 	if err != nil {
@@ -465,6 +760,51 @@ func (ctrl *ComponentController) GetAllComponents(c *gin.Context) {
 	utils.SuccessResponse(c, "Components fetched successfully", components)
 }
 
+// GetMyComponents lists the components created by the authenticated vendor, inactive ones included
+func (ctrl *ComponentController) GetMyComponents(c *gin.Context) {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return
+	}
+
+	var pagination repositories.PaginationParams
+	pagination.Page = 1
+	pagination.PageSize = 12
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		pagination.Page = page
+	}
+
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 && pageSize <= 100 {
+		pagination.PageSize = pageSize
+	}
+
+	response, err := ctrl.repo.GetComponentsByCreator(userID, pagination)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch components", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Components fetched successfully", response)
+}
+
+// canMutateComponent lets admins change any component and vendors only the ones they created,
+// and writes a forbidden response otherwise
+func canMutateComponent(c *gin.Context, component *models.Component) bool {
+	if c.GetString("user_role") != models.RoleVendor {
+		return true
+	}
+
+	userID, ok := getCurrentUserID(c)
+	if ok && component.CreatedBy != nil && *component.CreatedBy == userID {
+		return true
+	}
+
+	utils.ForbiddenError(c, "You can only modify components you created")
+	return false
+}
+
 // Helper function to determine if a spec is filterable
 func isFilterableSpec(key string) bool {
 	filterableSpecs := []string{
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"log"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"pc-builder/backend/services"
+	"pc-builder/backend/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ExchangeRateController struct {
+	repo   *repositories.ExchangeRateRepository
+	alerts *services.PriceAlertService
+	db     *gorm.DB
+}
+
+func NewExchangeRateController(db *gorm.DB, alerts *services.PriceAlertService) *ExchangeRateController {
+	return &ExchangeRateController{
+		repo:   repositories.NewExchangeRateRepository(db),
+		alerts: alerts,
+		db:     db,
+	}
+}
+
+func (ctrl *ExchangeRateController) GetExchangeRates(c *gin.Context) {
+	rates, err := ctrl.repo.GetExchangeRates()
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch exchange rates", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Exchange rates fetched successfully", gin.H{
+		"base_currency": models.BaseCurrency,
+		"rates":         rates,
+	})
+}
+
+// PutExchangeRate creates or replaces the rate of the :currency param. The base currency
+// always keeps a rate of 1.
+func (ctrl *ExchangeRateController) PutExchangeRate(c *gin.Context) {
+	currency := strings.ToUpper(strings.TrimSpace(c.Param("currency")))
+	if currency == "" || len(currency) > 10 {
+		utils.BadRequestError(c, "Invalid currency", nil)
+		return
+	}
+
+	var request struct {
+		Rate     float64 `json:"rate" binding:"required,gt=0"`
+		Symbol   string  `json:"symbol" binding:"max=10"`
+		Decimals *int    `json:"decimals" binding:"omitempty,min=0,max=8"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	if currency == models.BaseCurrency && request.Rate != 1 {
+		utils.BadRequestError(c, "The base currency rate must be 1", nil)
+		return
+	}
+
+	rate := &models.ExchangeRate{
+		Currency:  currency,
+		Rate:      request.Rate,
+		Symbol:    strings.TrimSpace(request.Symbol),
+		Decimals:  2,
+		UpdatedBy: currentUserRef(c),
+	}
+	if request.Decimals != nil {
+		rate.Decimals = *request.Decimals
+	}
+
+	previousRates, err := ctrl.repo.GetExchangeRates()
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch exchange rates", err)
+		return
+	}
+
+	if err := ctrl.repo.UpsertExchangeRate(rate); err != nil {
+		utils.InternalServerError(c, "Failed to save exchange rate", err)
+		return
+	}
+
+	ctrl.checkRateChange(previousRates)
+
+	saved, err := ctrl.repo.GetExchangeRate(currency)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch exchange rate", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Exchange rate saved successfully", saved)
+}
+
+func (ctrl *ExchangeRateController) DeleteExchangeRate(c *gin.Context) {
+	currency := strings.ToUpper(strings.TrimSpace(c.Param("currency")))
+	if currency == models.BaseCurrency {
+		utils.BadRequestError(c, "The base currency rate cannot be deleted", nil)
+		return
+	}
+
+	if _, err := ctrl.repo.GetExchangeRate(currency); err != nil {
+		utils.NotFoundError(c, "Exchange rate not found")
+		return
+	}
+
+	previousRates, err := ctrl.repo.GetExchangeRates()
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch exchange rates", err)
+		return
+	}
+
+	if err := ctrl.repo.DeleteExchangeRate(currency); err != nil {
+		utils.InternalServerError(c, "Failed to delete exchange rate", err)
+		return
+	}
+
+	ctrl.checkRateChange(previousRates)
+
+	utils.NoContentResponse(c)
+}
+
+// checkRateChange re-checks the price alerts against the derived prices of the new rates. A
+// failing check is logged, the rate change itself is kept.
+func (ctrl *ExchangeRateController) checkRateChange(previousRates []models.ExchangeRate) {
+	currentRates, err := ctrl.repo.GetExchangeRates()
+	if err == nil {
+		err = ctrl.alerts.CheckRateChange(previousRates, currentRates)
+	}
+	if err != nil {
+		log.Printf("⚠️ Failed to process price alerts for the exchange rate change: %v", err)
+	}
+}
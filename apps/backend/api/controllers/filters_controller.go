@@ -1,7 +1,9 @@
 package controllers
 
 import (
+	"errors"
 	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
 	"pc-builder/backend/utils"
 	"strings"
 
@@ -10,12 +12,16 @@ import (
 )
 
 func (ctrl *ComponentController) GetAvailableFilters(c *gin.Context) {
-	lang := c.GetHeader("Accept-Language")
-	if lang == "" {
-		lang = "vn"
+	currency := strings.ToUpper(strings.TrimSpace(c.Query("currency")))
+	if currency == "" {
+		lang := c.GetHeader("Accept-Language")
+		if lang == "" {
+			lang = "vn"
+		}
+		currency = repositories.CurrencyForLang(lang)
 	}
 
-	filters, err := ctrl.repo.GetAvailableFilters(lang)
+	filters, err := ctrl.repo.GetAvailableFilters(currency)
 	if err != nil {
 		utils.InternalServerError(c, "Failed to fetch available filters", err)
 		return
@@ -233,3 +239,35 @@ func (ctrl *ComponentController) UpdateCategory(c *gin.Context) {
 
 	utils.SuccessResponse(c, "Category updated successfully", nil)
 }
+
+// DeleteCategory moves a category to the trash, its components are hidden until it is restored
+func (ctrl *ComponentController) DeleteCategory(c *gin.Context) {
+	err := ctrl.trash.MoveToTrash(repositories.TrashCategories, c.Param("id"), currentUserRef(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.NotFoundError(c, "Category not found")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to delete category", err)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// DeleteBrand moves a brand to the trash, components no longer list it until it is restored
+func (ctrl *ComponentController) DeleteBrand(c *gin.Context) {
+	err := ctrl.trash.MoveToTrash(repositories.TrashBrands, c.Param("id"), currentUserRef(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.NotFoundError(c, "Brand not found")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to delete brand", err)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"pc-builder/backend/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OfferController struct {
+	repo       *repositories.OfferRepository
+	components *repositories.ComponentRepository
+	db         *gorm.DB
+}
+
+func NewOfferController(db *gorm.DB) *OfferController {
+	return &OfferController{
+		repo:       repositories.NewOfferRepository(db),
+		components: repositories.NewComponentRepository(db),
+		db:         db,
+	}
+}
+
+// GetMyOffers lists the offers of the authenticated vendor, optionally for one component
+func (ctrl *OfferController) GetMyOffers(c *gin.Context) {
+	vendorID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return
+	}
+
+	offers, err := ctrl.repo.GetOffersByVendor(vendorID, c.Query("component_id"))
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch offers", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Offers fetched successfully", offers)
+}
+
+func (ctrl *OfferController) CreateOffer(c *gin.Context) {
+	vendorID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return
+	}
+
+	var request struct {
+		ComponentID   string       `json:"component_id" binding:"required"`
+		Price         models.Price `json:"price" binding:"required,min=1"`
+		StockQuantity int          `json:"stock_quantity" binding:"min=0"`
+		URL           string       `json:"url" binding:"omitempty,url,max=500"`
+		Condition     string       `json:"condition" binding:"omitempty,oneof=new open_box refurbished used"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	priceJSON, err := offerPriceJSON(request.Price)
+	if err != nil {
+		utils.BadRequestError(c, err.Error(), err)
+		return
+	}
+
+	if _, err := ctrl.components.GetComponentByID(request.ComponentID); err != nil {
+		utils.BadRequestError(c, "Invalid component ID", err)
+		return
+	}
+
+	offer := &models.Offer{
+		ComponentID:   request.ComponentID,
+		VendorID:      vendorID,
+		Price:         priceJSON,
+		StockQuantity: request.StockQuantity,
+		URL:           strings.TrimSpace(request.URL),
+		Condition:     request.Condition,
+		IsActive:      true,
+	}
+	if offer.Condition == "" {
+		offer.Condition = models.OfferConditionNew
+	}
+
+	if err := ctrl.repo.CreateOffer(offer); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			utils.ConflictError(c, "You already have an offer for this component in this condition")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to create offer", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Offer created successfully", offer)
+}
+
+func (ctrl *OfferController) UpdateOffer(c *gin.Context) {
+	offer, ok := ctrl.findOwnedOffer(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Price         models.Price `json:"price"`
+		StockQuantity *int         `json:"stock_quantity" binding:"omitempty,min=0"`
+		URL           *string      `json:"url" binding:"omitempty,url,max=500"`
+		Condition     string       `json:"condition" binding:"omitempty,oneof=new open_box refurbished used"`
+		IsActive      *bool        `json:"is_active"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if len(request.Price) > 0 {
+		priceJSON, err := offerPriceJSON(request.Price)
+		if err != nil {
+			utils.BadRequestError(c, err.Error(), err)
+			return
+		}
+		updates["price"] = priceJSON
+	}
+	if request.StockQuantity != nil {
+		updates["stock_quantity"] = *request.StockQuantity
+	}
+	if request.URL != nil {
+		updates["url"] = strings.TrimSpace(*request.URL)
+	}
+	if request.Condition != "" {
+		updates["condition"] = request.Condition
+	}
+	if request.IsActive != nil {
+		updates["is_active"] = *request.IsActive
+	}
+
+	if len(updates) == 0 {
+		utils.BadRequestError(c, "No fields to update", nil)
+		return
+	}
+
+	if err := ctrl.repo.UpdateOffer(offer, updates); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			utils.ConflictError(c, "You already have an offer for this component in this condition")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to update offer", err)
+		return
+	}
+
+	updated, err := ctrl.repo.GetOfferByID(offer.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch offer", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Offer updated successfully", updated)
+}
+
+func (ctrl *OfferController) DeleteOffer(c *gin.Context) {
+	offer, ok := ctrl.findOwnedOffer(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.repo.DeleteOffer(offer.ID); err != nil {
+		utils.InternalServerError(c, "Failed to delete offer", err)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+func (ctrl *OfferController) findOwnedOffer(c *gin.Context) (*models.Offer, bool) {
+	vendorID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.NotFoundError(c, "Offer not found")
+		return nil, false
+	}
+
+	offer, err := ctrl.repo.GetOfferByID(id)
+	if err != nil || offer.VendorID != vendorID {
+		utils.NotFoundError(c, "Offer not found")
+		return nil, false
+	}
+
+	return offer, true
+}
+
+// offerPriceJSON validates the offer price, one positive amount per currency, and drops
+// derived entries like component prices
+func offerPriceJSON(price models.Price) (json.RawMessage, error) {
+	price = price.Stored()
+	if len(price) == 0 {
+		return nil, fmt.Errorf("price needs at least one currency")
+	}
+
+	seen := make(map[string]bool)
+	for i, item := range price {
+		currency := strings.ToUpper(strings.TrimSpace(item.Currency))
+		if currency == "" || item.Amount <= 0 {
+			return nil, fmt.Errorf("every price needs a currency and a positive amount")
+		}
+		if seen[currency] {
+			return nil, fmt.Errorf("price lists %s more than once", currency)
+		}
+		seen[currency] = true
+		price[i].Currency = currency
+	}
+
+	return json.Marshal(price)
+}
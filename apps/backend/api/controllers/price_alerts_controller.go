@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"pc-builder/backend/services"
+	"pc-builder/backend/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PriceAlertController struct {
+	repo       *repositories.PriceAlertRepository
+	components *repositories.ComponentRepository
+	builds     *repositories.BuildRepository
+	service    *services.PriceAlertService
+	db         *gorm.DB
+}
+
+func NewPriceAlertController(db *gorm.DB, service *services.PriceAlertService) *PriceAlertController {
+	return &PriceAlertController{
+		repo:       repositories.NewPriceAlertRepository(db),
+		components: repositories.NewComponentRepository(db),
+		builds:     repositories.NewBuildRepository(db),
+		service:    service,
+		db:         db,
+	}
+}
+
+// GetMyAlerts lists the alerts triggered for the authenticated user, latest first
+func (ctrl *PriceAlertController) GetMyAlerts(c *gin.Context) {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return
+	}
+
+	var pagination repositories.PaginationParams
+	pagination.Page = 1
+	pagination.PageSize = 20
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		pagination.Page = page
+	}
+
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 && pageSize <= 100 {
+		pagination.PageSize = pageSize
+	}
+
+	response, err := ctrl.repo.GetAlertsByUser(userID, pagination)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch price alerts", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Price alerts fetched successfully", response)
+}
+
+func (ctrl *PriceAlertController) GetMySubscriptions(c *gin.Context) {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return
+	}
+
+	subscriptions, err := ctrl.repo.GetSubscriptionsByUser(userID)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch price alert subscriptions", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Price alert subscriptions fetched successfully", gin.H{
+		"subscriptions": subscriptions,
+		"channels":      ctrl.service.Notifiers(),
+	})
+}
+
+// CreateSubscription watches a component, or the total of a build the user can see
+func (ctrl *PriceAlertController) CreateSubscription(c *gin.Context) {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return
+	}
+
+	var request struct {
+		ComponentID string  `json:"component_id"`
+		BuildID     string  `json:"build_id"`
+		TargetPrice float64 `json:"target_price" binding:"required,gt=0"`
+		Currency    string  `json:"currency" binding:"required,max=10"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	if (request.ComponentID == "") == (request.BuildID == "") {
+		utils.BadRequestError(c, "Exactly one of component_id or build_id is required", nil)
+		return
+	}
+
+	subscription := &models.PriceAlertSubscription{
+		UserID:      userID,
+		TargetPrice: request.TargetPrice,
+		Currency:    strings.ToUpper(strings.TrimSpace(request.Currency)),
+		IsActive:    true,
+	}
+
+	if request.ComponentID != "" {
+		if _, err := ctrl.components.GetComponentByID(request.ComponentID); err != nil {
+			utils.BadRequestError(c, "Invalid component ID", err)
+			return
+		}
+		subscription.ComponentID = &request.ComponentID
+	} else {
+		buildID, err := uuid.Parse(request.BuildID)
+		if err != nil {
+			utils.BadRequestError(c, "Invalid build ID", err)
+			return
+		}
+
+		build, err := ctrl.builds.GetBuildByID(buildID)
+		if err != nil || (build.UserID != userID && !build.IsPublic) {
+			utils.BadRequestError(c, "Invalid build ID", err)
+			return
+		}
+		subscription.BuildID = &buildID
+	}
+
+	if err := ctrl.repo.CreateSubscription(subscription); err != nil {
+		utils.InternalServerError(c, "Failed to create price alert subscription", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Price alert subscription created successfully", subscription)
+}
+
+func (ctrl *PriceAlertController) UpdateSubscription(c *gin.Context) {
+	subscription, ok := ctrl.findOwnedSubscription(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		TargetPrice *float64 `json:"target_price" binding:"omitempty,gt=0"`
+		Currency    string   `json:"currency" binding:"max=10"`
+		IsActive    *bool    `json:"is_active"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.BadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if request.TargetPrice != nil {
+		updates["target_price"] = *request.TargetPrice
+	}
+	if currency := strings.TrimSpace(request.Currency); currency != "" {
+		updates["currency"] = strings.ToUpper(currency)
+	}
+	if request.IsActive != nil {
+		updates["is_active"] = *request.IsActive
+	}
+
+	if len(updates) == 0 {
+		utils.BadRequestError(c, "No fields to update", nil)
+		return
+	}
+
+	if err := ctrl.repo.UpdateSubscription(subscription, updates); err != nil {
+		utils.InternalServerError(c, "Failed to update price alert subscription", err)
+		return
+	}
+
+	updated, err := ctrl.repo.GetSubscriptionByID(subscription.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch price alert subscription", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Price alert subscription updated successfully", updated)
+}
+
+func (ctrl *PriceAlertController) DeleteSubscription(c *gin.Context) {
+	subscription, ok := ctrl.findOwnedSubscription(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.repo.DeleteSubscription(subscription.ID); err != nil {
+		utils.InternalServerError(c, "Failed to delete price alert subscription", err)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+func (ctrl *PriceAlertController) findOwnedSubscription(c *gin.Context) (*models.PriceAlertSubscription, bool) {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		utils.UnauthorizedError(c, "Unauthorized")
+		return nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.NotFoundError(c, "Price alert subscription not found")
+		return nil, false
+	}
+
+	subscription, err := ctrl.repo.GetSubscriptionByID(id)
+	if err != nil || subscription.UserID != userID {
+		utils.NotFoundError(c, "Price alert subscription not found")
+		return nil, false
+	}
+
+	return subscription, true
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"errors"
+	"pc-builder/backend/api/repositories"
+	"pc-builder/backend/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TrashController struct {
+	repo *repositories.TrashRepository
+	db   *gorm.DB
+}
+
+func NewTrashController(db *gorm.DB) *TrashController {
+	return &TrashController{
+		repo: repositories.NewTrashRepository(db),
+		db:   db,
+	}
+}
+
+// GetTrash lists the trashed components, categories or brands picked by ?type
+func (ctrl *TrashController) GetTrash(c *gin.Context) {
+	kind := c.DefaultQuery("type", repositories.TrashComponents)
+	if !repositories.IsTrashType(kind) {
+		utils.BadRequestError(c, "type must be components, categories or brands", nil)
+		return
+	}
+
+	var pagination repositories.PaginationParams
+	pagination.Page = 1
+	pagination.PageSize = 20
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		pagination.Page = page
+	}
+
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 && pageSize <= 100 {
+		pagination.PageSize = pageSize
+	}
+
+	response, err := ctrl.repo.GetTrash(kind, pagination)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch trash", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Trash fetched successfully", response)
+}
+
+func (ctrl *TrashController) RestoreFromTrash(c *gin.Context) {
+	kind, ok := trashTypeParam(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.repo.Restore(kind, c.Param("id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.NotFoundError(c, "Item not found in trash")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to restore item", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Item restored successfully", nil)
+}
+
+// PurgeFromTrash permanently deletes a trashed item, live items have to be trashed first
+func (ctrl *TrashController) PurgeFromTrash(c *gin.Context) {
+	kind, ok := trashTypeParam(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.repo.Purge(kind, c.Param("id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.NotFoundError(c, "Item not found in trash")
+			return
+		}
+
+		for sentinel, message := range purgeConflictMessages {
+			if errors.Is(err, sentinel) {
+				utils.ConflictError(c, message)
+				return
+			}
+		}
+
+		if strings.Contains(err.Error(), "foreign key") {
+			utils.ConflictError(c, "Item is still referenced by other records")
+			return
+		}
+
+		utils.InternalServerError(c, "Failed to purge item", err)
+		return
+	}
+
+	utils.NoContentResponse(c)
+}
+
+// purgeConflictMessages explains which rows still reference the purged one
+var purgeConflictMessages = map[error]string{
+	repositories.ErrUsedByBuilds:  "Component is still used by saved builds, it can stay in the trash or be restored",
+	repositories.ErrCategoryInUse: "Category still has components, purge or move them first",
+	repositories.ErrBrandInUse:    "Brand is still assigned to components, purge or reassign them first",
+}
+
+func trashTypeParam(c *gin.Context) (string, bool) {
+	kind := c.Param("type")
+	if !repositories.IsTrashType(kind) {
+		utils.NotFoundError(c, "Unknown trash type")
+		return "", false
+	}
+	return kind, true
+}
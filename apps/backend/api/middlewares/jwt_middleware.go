@@ -22,14 +22,8 @@ func JWTMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
-
-		claims := &utils.Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, ok := parseToken(tokenString)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"status":  http.StatusUnauthorized,
 				"message": "Invalid or expired token",
@@ -38,9 +32,42 @@ func JWTMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		c.Set("user_id", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
+		setClaims(c, claims)
 		c.Next()
 	}
 }
+
+// OptionalJWTMiddleware identifies the caller of a public route when a valid token is sent,
+// and lets anonymous requests through
+func OptionalJWTMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, ok := parseToken(c.GetHeader("Authorization")); ok {
+			setClaims(c, claims)
+		}
+		c.Next()
+	}
+}
+
+func parseToken(header string) (*utils.Claims, bool) {
+	if header == "" {
+		return nil, false
+	}
+
+	tokenString := strings.Replace(header, "Bearer ", "", 1)
+
+	claims := &utils.Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(os.Getenv("JWT_SECRET")), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, false
+	}
+
+	return claims, true
+}
+
+func setClaims(c *gin.Context, claims *utils.Claims) {
+	c.Set("user_id", claims.UserID)
+	c.Set("email", claims.Email)
+	c.Set("role", claims.Role)
+}
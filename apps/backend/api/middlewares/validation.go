@@ -3,6 +3,7 @@ package middlewares
 import (
 	"net/http"
 	"strings"
+	"unicode"
 
 	"github.com/gin-gonic/gin"
 )
@@ -59,6 +60,41 @@ func ValidateQueryParams() gin.HandlerFunc {
 			}
 		}
 
+		if compatibleWith := c.Query("compatible_with"); compatibleWith != "" {
+			if len(compatibleWith) > 2000 {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"status":  http.StatusBadRequest,
+					"message": "compatible_with too long (max 2000 characters)",
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		if currency := c.Query("currency"); currency != "" {
+			if len(currency) > 10 || strings.IndexFunc(currency, func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsSpace(r)
+			}) >= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"status":  http.StatusBadRequest,
+					"message": "Invalid currency parameter",
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		if ids := c.Query("ids"); ids != "" {
+			if len(ids) > 2000 {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"status":  http.StatusBadRequest,
+					"message": "ids too long (max 2000 characters)",
+				})
+				c.Abort()
+				return
+			}
+		}
+
 		c.Next()
 	}
 }
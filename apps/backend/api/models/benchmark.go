@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Benchmark is one measured score of a component, such as its Cinebench R23 multi-core result
+type Benchmark struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ComponentID string    `json:"component_id" gorm:"size:255;not null;index"`
+	Name        string    `json:"name" gorm:"size:100;not null;index"` // e.g. "cinebench_r23_multi"
+	Score       float64   `json:"score" gorm:"not null"`
+	Source      string    `json:"source" gorm:"size:255;not null;default:''"`
+	MeasuredAt  time.Time `json:"measured_at" gorm:"type:date"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Component *Component `json:"-" gorm:"foreignKey:ComponentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// BenchmarkScore is the benchmark as exposed on a component
+type BenchmarkScore struct {
+	Name       string    `json:"name"`
+	Score      float64   `json:"score"`
+	Source     string    `json:"source"`
+	MeasuredAt time.Time `json:"measured_at"`
+}
+
+// DefaultBenchmarks is the benchmark used to rank a category by price per performance when
+// the request does not name one
+var DefaultBenchmarks = map[string]string{
+	CategoryCPU: "cinebench_r23_multi",
+	CategoryGPU: "3dmark_time_spy",
+}
+
+// GamingCPUBenchmark tracks game performance better than the multi-core default
+const GamingCPUBenchmark = "cinebench_r23_single"
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Build struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID        uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name          string     `json:"name" gorm:"size:255;not null"`
+	Notes         string     `json:"notes" gorm:"type:text"`
+	IsPublic      bool       `json:"is_public" gorm:"default:false"`
+	ShareSlug     *string    `json:"share_slug" gorm:"size:16;uniqueIndex"` // Set while the build is shared
+	ViewCount     int64      `json:"view_count" gorm:"default:0"`
+	ParentBuildID *uuid.UUID `json:"parent_build_id" gorm:"type:uuid;index"` // Build this one was forked from
+	ForkCount     int64      `json:"fork_count" gorm:"default:0"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	User        *User            `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	ParentBuild *Build           `json:"-" gorm:"foreignKey:ParentBuildID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Components  []BuildComponent `json:"components" gorm:"foreignKey:BuildID"`
+}
+
+// BuildComponent is a single slot of a build pointing at a catalog component
+type BuildComponent struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	BuildID     uuid.UUID `json:"build_id" gorm:"type:uuid;not null;index"`
+	ComponentID string    `json:"component_id" gorm:"size:255;not null;index"`
+	Quantity    int       `json:"quantity" gorm:"not null;default:1"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	Build     *Build     `json:"-" gorm:"foreignKey:BuildID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Component *Component `json:"component,omitempty" gorm:"foreignKey:ComponentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+type BuildWithTotals struct {
+	Build
+	TotalItems int          `json:"total_items"`
+	Totals     Price        `json:"totals"` // One entry per currency found on the components
+	ForkedFrom *BuildParent `json:"forked_from,omitempty"`
+}
+
+// BuildParent is the summary of the build a fork was made from. The name is only
+// exposed while the parent is public or owned by the same user.
+type BuildParent struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	ShareSlug *string   `json:"share_slug,omitempty"`
+}
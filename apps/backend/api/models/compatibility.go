@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// CompatibilityRule states that spec SourceSpecKey of a SourceCategoryID part must relate
+// to spec TargetSpecKey of every TargetCategoryID part in the build through Operator
+type CompatibilityRule struct {
+	ID               string    `json:"id" gorm:"primaryKey;size:100"`
+	Name             string    `json:"name" gorm:"size:255;not null"`
+	SourceCategoryID string    `json:"source_category_id" gorm:"size:50;not null;index"`
+	SourceSpecKey    string    `json:"source_spec_key" gorm:"size:100;not null"`
+	Operator         string    `json:"operator" gorm:"size:20;not null"`
+	TargetCategoryID string    `json:"target_category_id" gorm:"size:50;not null;index"`
+	TargetSpecKey    string    `json:"target_spec_key" gorm:"size:100;not null"`
+	Severity         string    `json:"severity" gorm:"size:20;not null;default:'error'"`
+	Message          string    `json:"message" gorm:"type:text;not null"`
+	IsActive         bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+const (
+	RuleSeverityError   = "error"
+	RuleSeverityWarning = "warning"
+
+	RuleOperatorEquals   = "equals"   // Source value equals target value
+	RuleOperatorContains = "contains" // Source list contains every target value
+	RuleOperatorLTE      = "lte"      // Source number is less than or equal to target number
+	RuleOperatorGTE      = "gte"      // Source number is greater than or equal to target number
+)
+
+var RuleSeverities = []string{RuleSeverityError, RuleSeverityWarning}
+
+var RuleOperators = []string{RuleOperatorEquals, RuleOperatorContains, RuleOperatorLTE, RuleOperatorGTE}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Revision actions
+const (
+	RevisionActionCreate   = "create"
+	RevisionActionUpdate   = "update"
+	RevisionActionRollback = "rollback"
+	RevisionActionApprove  = "approve" // Vendor changes applied by an admin review
+	RevisionActionImport   = "import"  // Backfilled for components created before revisions
+)
+
+// ComponentRevision is an immutable snapshot of a component written after every change.
+// Revisions are numbered from 1 per component.
+type ComponentRevision struct {
+	ID          uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	ComponentID string          `json:"component_id" gorm:"size:255;not null;uniqueIndex:idx_component_revisions_component_revision"`
+	Revision    int             `json:"revision" gorm:"not null;uniqueIndex:idx_component_revisions_component_revision"`
+	Action      string          `json:"action" gorm:"size:20;not null"`
+	Snapshot    json.RawMessage `json:"snapshot" gorm:"type:jsonb;not null"` // A ComponentSnapshot
+	CreatedBy   *uuid.UUID      `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt   time.Time       `json:"created_at" gorm:"autoCreateTime"`
+
+	Component *Component `json:"-" gorm:"foreignKey:ComponentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// ComponentSnapshot is the state of a component kept by a revision
+type ComponentSnapshot struct {
+	Name       string            `json:"name"`
+	CategoryID string            `json:"category_id"`
+	Models     string            `json:"models"`
+	IsActive   bool              `json:"is_active"`
+	InStock    bool              `json:"in_stock"`
+	Price      Price             `json:"price"`
+	ImageURL   ImageURL          `json:"image_url"`
+	Brands     []SnapshotBrand   `json:"brands"` // Primary brand first
+	Specs      map[string]string `json:"specs"`
+}
+
+// ComponentChanges holds only the fields a vendor changed on a published component, so
+// approving them keeps whatever else was edited in the meantime. Nil fields are unchanged.
+type ComponentChanges struct {
+	Name       *string           `json:"name,omitempty"`
+	CategoryID *string           `json:"category_id,omitempty"`
+	Models     *string           `json:"models,omitempty"`
+	IsActive   *bool             `json:"is_active,omitempty"`
+	InStock    *bool             `json:"in_stock,omitempty"`
+	Price      Price             `json:"price,omitempty"`
+	ImageURL   ImageURL          `json:"image_url,omitempty"`
+	Brands     []SnapshotBrand   `json:"brands,omitempty"`
+	Specs      map[string]string `json:"specs,omitempty"`
+}
+
+// Merge returns the changes with the fields set in newer replacing their own
+func (c ComponentChanges) Merge(newer ComponentChanges) ComponentChanges {
+	if newer.Name != nil {
+		c.Name = newer.Name
+	}
+	if newer.CategoryID != nil {
+		c.CategoryID = newer.CategoryID
+	}
+	if newer.Models != nil {
+		c.Models = newer.Models
+	}
+	if newer.IsActive != nil {
+		c.IsActive = newer.IsActive
+	}
+	if newer.InStock != nil {
+		c.InStock = newer.InStock
+	}
+	if newer.Price != nil {
+		c.Price = newer.Price
+	}
+	if newer.ImageURL != nil {
+		c.ImageURL = newer.ImageURL
+	}
+	if newer.Brands != nil {
+		c.Brands = newer.Brands
+	}
+	if newer.Specs != nil {
+		c.Specs = newer.Specs
+	}
+	return c
+}
+
+// Apply returns the snapshot with the changed fields replaced
+func (c ComponentChanges) Apply(snapshot ComponentSnapshot) ComponentSnapshot {
+	if c.Name != nil {
+		snapshot.Name = *c.Name
+	}
+	if c.CategoryID != nil {
+		snapshot.CategoryID = *c.CategoryID
+	}
+	if c.Models != nil {
+		snapshot.Models = *c.Models
+	}
+	if c.IsActive != nil {
+		snapshot.IsActive = *c.IsActive
+	}
+	if c.InStock != nil {
+		snapshot.InStock = *c.InStock
+	}
+	if c.Price != nil {
+		snapshot.Price = c.Price
+	}
+	if c.ImageURL != nil {
+		snapshot.ImageURL = c.ImageURL
+	}
+	if c.Brands != nil {
+		snapshot.Brands = c.Brands
+	}
+	if c.Specs != nil {
+		snapshot.Specs = c.Specs
+	}
+	return snapshot
+}
+
+type SnapshotBrand struct {
+	BrandID   string `json:"brand_id"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// RevisionChange is one field that differs between two revisions. Specs are compared
+// key by key as "specs.<key>", a missing spec is nil.
+type RevisionChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// ParseSnapshot decodes the snapshot of a revision
+func (r ComponentRevision) ParseSnapshot() (ComponentSnapshot, error) {
+	var snapshot ComponentSnapshot
+	err := json.Unmarshal(r.Snapshot, &snapshot)
+	return snapshot, err
+}
+
+// DiffSnapshots lists the fields that changed from one snapshot to the other
+func DiffSnapshots(from, to ComponentSnapshot) []RevisionChange {
+	changes := []RevisionChange{}
+	add := func(field string, a, b interface{}) {
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, RevisionChange{Field: field, From: a, To: b})
+		}
+	}
+
+	add("name", from.Name, to.Name)
+	add("category_id", from.CategoryID, to.CategoryID)
+	add("models", from.Models, to.Models)
+	add("is_active", from.IsActive, to.IsActive)
+	add("in_stock", from.InStock, to.InStock)
+	add("price", emptyIfNil(from.Price), emptyIfNil(to.Price))
+	add("image_url", emptyIfNil(from.ImageURL), emptyIfNil(to.ImageURL))
+	add("brands", emptyIfNil(from.Brands), emptyIfNil(to.Brands))
+
+	keys := make([]string, 0, len(from.Specs)+len(to.Specs))
+	for key := range from.Specs {
+		keys = append(keys, key)
+	}
+	for key := range to.Specs {
+		if _, exists := from.Specs[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		add("specs."+key, specValue(from.Specs, key), specValue(to.Specs, key))
+	}
+
+	return changes
+}
+
+// emptyIfNil makes a nil and an empty list compare equal and encode as []
+func emptyIfNil[T any](list []T) []T {
+	if list == nil {
+		return []T{}
+	}
+	return list
+}
+
+func specValue(specs map[string]string, key string) interface{} {
+	if value, exists := specs[key]; exists {
+		return value
+	}
+	return nil
+}
@@ -3,8 +3,38 @@ package models
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// TrashFields soft delete a catalog row. GORM leaves trashed rows out of model queries,
+// joins and raw SQL have to check deleted_at themselves.
+type TrashFields struct {
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	DeletedBy *uuid.UUID     `json:"deleted_by,omitempty" gorm:"type:uuid"`
+}
+
+// Component statuses. Only published components are listed publicly, vendor components
+// start as drafts and are published by an admin.
+const (
+	ComponentStatusDraft         = "draft"
+	ComponentStatusPendingReview = "pending_review"
+	ComponentStatusPublished     = "published"
+	ComponentStatusRejected      = "rejected"
+)
+
+// ReviewFields track the moderation of a component. Vendor changes to a published component
+// wait in PendingChanges, the live component is left as is until they are approved.
+type ReviewFields struct {
+	Status         string          `json:"status" gorm:"size:20;not null;default:'published';index"`
+	SubmittedAt    *time.Time      `json:"submitted_at,omitempty"`
+	ReviewNote     string          `json:"review_note,omitempty" gorm:"type:text"` // Reason of the last rejection
+	ReviewedBy     *uuid.UUID      `json:"reviewed_by,omitempty" gorm:"type:uuid"`
+	ReviewedAt     *time.Time      `json:"reviewed_at,omitempty"`
+	PendingChanges json.RawMessage `json:"pending_changes,omitempty" gorm:"type:jsonb"` // A ComponentChanges
+}
+
 type Category struct {
 	ID          string    `json:"id" gorm:"primaryKey;size:50"`
 	Name        string    `json:"name" gorm:"size:50;uniqueIndex;not null"`
@@ -15,6 +45,7 @@ type Category struct {
 	IsActive    bool      `json:"is_active" gorm:"default:true"`
 	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	TrashFields
 
 	Components []Component `json:"components,omitempty" gorm:"foreignKey:CategoryID"`
 }
@@ -29,6 +60,7 @@ type Brand struct {
 	IsActive    bool      `json:"is_active" gorm:"default:true"`
 	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	TrashFields
 
 	Components []Component `json:"components,omitempty" gorm:"many2many:component_brands;"`
 }
@@ -42,8 +74,11 @@ type Component struct {
 	ImageURL   json.RawMessage `json:"image_url" gorm:"type:jsonb;default:'[]'"`
 	IsActive   bool            `json:"is_active" gorm:"default:true"`
 	InStock    bool            `json:"in_stock" gorm:"default:true"`
+	CreatedBy  *uuid.UUID      `json:"created_by,omitempty" gorm:"type:uuid;index"` // Owner of vendor-created components
 	CreatedAt  time.Time       `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt  time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+	TrashFields
+	ReviewFields
 
 	Category *Category       `json:"category,omitempty" gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
 	Brands   []Brand         `json:"brands,omitempty" gorm:"many2many:component_brands;"`
@@ -80,13 +115,49 @@ type ComponentWithRelations struct {
 	BrandDisplays   []string          `json:"brand_displays"` // Multiple brand display names
 	PrimaryBrand    string            `json:"primary_brand"`  // Main manufacturer
 	SpecsMap        map[string]string `json:"specs_map"`
+	Benchmarks      []BenchmarkScore  `json:"benchmarks" gorm:"-"`
+	Offers          []Offer           `json:"offers" gorm:"-"`     // Active vendor offers, cheapest first
+	BestPrice       []BestOfferPrice  `json:"best_price" gorm:"-"` // One entry per currency
 }
 
 type PriceItem struct {
 	Currency string  `json:"currency"`
 	Amount   float64 `json:"amount"`
 	Symbol   string  `json:"symbol,omitempty"`
+	Derived  bool    `json:"derived,omitempty"` // Converted from another currency, never stored
 }
 
 type Price []PriceItem
 type ImageURL []string
+
+// ParsePrice decodes the price JSONB column, an invalid value gives an empty price list
+func ParsePrice(raw json.RawMessage) Price {
+	var price Price
+	if err := json.Unmarshal(raw, &price); err != nil {
+		return Price{}
+	}
+	return price
+}
+
+// Find returns the entry of the given currency
+func (p Price) Find(currency string) (PriceItem, bool) {
+	for _, item := range p {
+		if item.Currency == currency {
+			return item, true
+		}
+	}
+	return PriceItem{}, false
+}
+
+// Category IDs used by the build compatibility tools
+const (
+	CategoryCPU       = "cpu"
+	CategoryMainboard = "mainboard"
+	CategoryRAM       = "ram"
+	CategoryGPU       = "gpu"
+	CategoryStorage   = "storage"
+	CategoryPSU       = "psu"
+	CategoryCase      = "case"
+	CategoryCooler    = "cooler"
+	CategoryFan       = "fan"
+)
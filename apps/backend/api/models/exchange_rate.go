@@ -0,0 +1,100 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BaseCurrency is the currency every exchange rate is expressed in. A component only needs
+// a price in it, the other currencies are derived on read.
+const BaseCurrency = "VND"
+
+// ExchangeRate is the value of one unit of the currency in the base currency, e.g. USD 25400
+type ExchangeRate struct {
+	Currency  string     `json:"currency" gorm:"primaryKey;size:10"`
+	Rate      float64    `json:"rate" gorm:"not null"`
+	Symbol    string     `json:"symbol" gorm:"size:10"`
+	Decimals  int        `json:"decimals" gorm:"not null"` // Derived amounts are rounded to it
+	UpdatedBy *uuid.UUID `json:"updated_by,omitempty" gorm:"type:uuid"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Stored drops the derived entries so only prices entered by hand are saved
+func (p Price) Stored() Price {
+	stored := Price{}
+	for _, item := range p {
+		if !item.Derived {
+			stored = append(stored, item)
+		}
+	}
+	return stored
+}
+
+// WithDerived adds an entry for every rated currency the price lacks. The amount is converted
+// from the base currency entry, or else from the first stored currency with a rate.
+func (p Price) WithDerived(rates []ExchangeRate) Price {
+	byCurrency := make(map[string]ExchangeRate, len(rates))
+	for _, rate := range rates {
+		byCurrency[rate.Currency] = rate
+	}
+
+	source, sourceRate, ok := p.conversionSource(byCurrency)
+	if !ok {
+		return p
+	}
+
+	currencies := make([]string, 0, len(rates))
+	for _, rate := range rates {
+		currencies = append(currencies, rate.Currency)
+	}
+	sort.Strings(currencies)
+
+	derived := append(Price{}, p...)
+	for _, currency := range currencies {
+		if _, exists := p.Find(currency); exists {
+			continue
+		}
+
+		target := byCurrency[currency]
+		if target.Rate <= 0 {
+			continue
+		}
+
+		scale := math.Pow(10, float64(target.Decimals))
+		derived = append(derived, PriceItem{
+			Currency: currency,
+			Amount:   math.Round(source.Amount*sourceRate.Rate/target.Rate*scale) / scale,
+			Symbol:   target.Symbol,
+			Derived:  true,
+		})
+	}
+
+	return derived
+}
+
+// conversionSource picks the entry prices are derived from, it matches the SQL used to
+// filter and sort by price
+func (p Price) conversionSource(rates map[string]ExchangeRate) (PriceItem, ExchangeRate, bool) {
+	if item, exists := p.Find(BaseCurrency); exists {
+		if rate, rated := rates[BaseCurrency]; rated {
+			return item, rate, true
+		}
+	}
+
+	var best PriceItem
+	var bestRate ExchangeRate
+	found := false
+	for _, item := range p {
+		rate, rated := rates[item.Currency]
+		if !rated || (found && item.Currency >= best.Currency) {
+			continue
+		}
+		best, bestRate, found = item, rate, true
+	}
+
+	return best, bestRate, found
+}
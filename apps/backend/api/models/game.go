@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Game holds the reference figures FPS estimates are scaled from. The reference FPS are
+// what a CPU or GPU with the reference benchmark score achieves at 1080p high; the CPU
+// figure does not depend on the resolution.
+type Game struct {
+	ID                string    `json:"id" gorm:"primaryKey;size:100"`
+	Name              string    `json:"name" gorm:"size:255;not null"`
+	CPUBenchmark      string    `json:"cpu_benchmark" gorm:"size:100;not null"`
+	GPUBenchmark      string    `json:"gpu_benchmark" gorm:"size:100;not null"`
+	ReferenceCPUScore float64   `json:"reference_cpu_score" gorm:"not null"`
+	ReferenceGPUScore float64   `json:"reference_gpu_score" gorm:"not null"`
+	ReferenceCPUFPS   float64   `json:"reference_cpu_fps" gorm:"not null"`
+	ReferenceGPUFPS   float64   `json:"reference_gpu_fps" gorm:"not null"`
+	IsActive          bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt         time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// GameFPSBenchmark is the benchmark name of a measured FPS result, which takes precedence
+// over the scaled estimate, e.g. "fps:cyberpunk_2077:1440p:high"
+func GameFPSBenchmark(gameID, resolution, preset string) string {
+	return "fps:" + gameID + ":" + resolution + ":" + preset
+}
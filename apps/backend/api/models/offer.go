@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Offer conditions
+const (
+	OfferConditionNew         = "new"
+	OfferConditionOpenBox     = "open_box"
+	OfferConditionRefurbished = "refurbished"
+	OfferConditionUsed        = "used"
+)
+
+// Offer is a vendor selling a catalog component. A vendor has at most one offer per
+// component and condition.
+type Offer struct {
+	ID            uuid.UUID       `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ComponentID   string          `json:"component_id" gorm:"size:255;not null;index"`
+	VendorID      uuid.UUID       `json:"vendor_id" gorm:"type:uuid;not null;index"`
+	Price         json.RawMessage `json:"price" gorm:"type:jsonb;default:'[]'"` // Same shape as Component.Price
+	StockQuantity int             `json:"stock_quantity" gorm:"not null;default:0"`
+	URL           string          `json:"url" gorm:"size:500"`
+	Condition     string          `json:"condition" gorm:"size:20;not null;default:'new'"`
+	IsActive      bool            `json:"is_active" gorm:"default:true"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Component *Component `json:"-" gorm:"foreignKey:ComponentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Vendor    *User      `json:"-" gorm:"foreignKey:VendorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// BestOfferPrice is the cheapest in-stock offer of a component in one currency
+type BestOfferPrice struct {
+	PriceItem
+	OfferID  uuid.UUID `json:"offer_id"`
+	VendorID uuid.UUID `json:"vendor_id"`
+}
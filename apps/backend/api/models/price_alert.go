@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AlertStatusPending = "pending"
+	AlertStatusSent    = "sent"
+	AlertStatusFailed  = "failed"
+)
+
+// PriceAlertSubscription watches either a component or the total of a build. It fires each
+// time a price update takes the watched amount from at or above the target to below it.
+type PriceAlertSubscription struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID          uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	ComponentID     *string    `json:"component_id,omitempty" gorm:"size:255;index"`
+	BuildID         *uuid.UUID `json:"build_id,omitempty" gorm:"type:uuid;index"`
+	TargetPrice     float64    `json:"target_price" gorm:"not null"`
+	Currency        string     `json:"currency" gorm:"size:10;not null"`
+	IsActive        bool       `json:"is_active" gorm:"default:true"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	User      *User      `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Component *Component `json:"-" gorm:"foreignKey:ComponentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Build     *Build     `json:"-" gorm:"foreignKey:BuildID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// PriceAlert is one triggered subscription and the outcome of its delivery. It is stored as
+// pending by the price update and delivered in the background.
+type PriceAlert struct {
+	ID             uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	SubscriptionID uuid.UUID  `json:"subscription_id" gorm:"type:uuid;not null;index"`
+	UserID         uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	ComponentID    *string    `json:"component_id,omitempty" gorm:"size:255"` // The component whose price changed
+	BuildID        *uuid.UUID `json:"build_id,omitempty" gorm:"type:uuid"`
+	Target         string     `json:"target" gorm:"size:255"` // Name of the watched component or build
+	Currency       string     `json:"currency" gorm:"size:10;not null"`
+	TargetPrice    float64    `json:"target_price" gorm:"not null"`
+	PreviousAmount float64    `json:"previous_amount"`
+	Amount         float64    `json:"amount" gorm:"not null"`
+	Status         string     `json:"status" gorm:"size:20;not null;default:'pending'"`
+	Error          string     `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+
+	Subscription *PriceAlertSubscription `json:"-" gorm:"foreignKey:SubscriptionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// EmailOutbox holds the emails waiting for a mail worker to send them
+type EmailOutbox struct {
+	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Recipient string     `json:"recipient" gorm:"size:255;not null"`
+	Subject   string     `json:"subject" gorm:"size:255;not null"`
+	Body      string     `json:"body" gorm:"type:text;not null"`
+	AlertID   *uint      `json:"alert_id,omitempty" gorm:"index"`
+	Status    string     `json:"status" gorm:"size:20;not null;default:'pending';index"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	SentAt    *time.Time `json:"sent_at"`
+}
+
+func (EmailOutbox) TableName() string {
+	return "email_outbox"
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceHistory is one recorded price of a component in a currency. A row is written
+// whenever the amount of a currency changes, so the latest row is the current price.
+type PriceHistory struct {
+	ID          uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	ComponentID string     `json:"component_id" gorm:"size:255;not null"`
+	Currency    string     `json:"currency" gorm:"size:10;not null"`
+	Amount      float64    `json:"amount" gorm:"not null"`
+	ChangedBy   *uuid.UUID `json:"changed_by,omitempty" gorm:"type:uuid"` // Nil for imports and backfilled rows
+	ChangedAt   time.Time  `json:"changed_at" gorm:"not null"`
+
+	Component *Component `json:"-" gorm:"foreignKey:ComponentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (PriceHistory) TableName() string {
+	return "price_history"
+}
+
+// PricePoint is one entry of a public price time series, without who changed it
+type PricePoint struct {
+	Amount    float64   `json:"amount"`
+	ChangedAt time.Time `json:"changed_at"`
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var specNumberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// specQuantityPattern reads a number with the unit written after it, as in "1 TB" or "32cm"
+var specQuantityPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*([a-zA-Z"]*)`)
+
+// specMultipliedPattern reads a single digit count before a quantity, such as "2x120mm" for a
+// 240 mm radiator or "2 x 16GB" for a 32 GB kit. Dimensions like "158x120" do not match.
+var specMultipliedPattern = regexp.MustCompile(`(?:^|[^\d.])(\d)\s*[x×*]\s*(\d+(?:\.\d+)?)\s*([a-zA-Z"]*)`)
+
+// Factors to the unit a spec key is stored in, picked by the key suffix
+var (
+	specLengthUnits   = map[string]float64{"": 1, "mm": 1, "cm": 10, "in": 25.4, "inch": 25.4, `"`: 25.4}
+	specCapacityUnits = map[string]float64{"": 1, "gb": 1, "tb": 1000, "mb": 0.001}
+)
+
+// ParseSpecNumber reads the first number of a value such as "320 mm" or "850W"
+func ParseSpecNumber(value string) (float64, bool) {
+	match := specNumberPattern.FindString(strings.ReplaceAll(value, ",", ""))
+	if match == "" {
+		return 0, false
+	}
+
+	number, err := strconv.ParseFloat(match, 64)
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
+
+// ParseSpecMaxNumber reads the largest number of a value, so "65-125W" gives 125
+func ParseSpecMaxNumber(value string) (float64, bool) {
+	matches := specNumberPattern.FindAllString(strings.ReplaceAll(value, ",", ""), -1)
+
+	found := false
+	largest := 0.0
+	for _, match := range matches {
+		number, err := strconv.ParseFloat(match, 64)
+		if err != nil {
+			continue
+		}
+		if !found || number > largest {
+			largest = number
+			found = true
+		}
+	}
+	return largest, found
+}
+
+// SplitSpecList splits a list value such as "240mm / 360mm" into its trimmed items
+func SplitSpecList(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == '/' || r == '|' || r == ';'
+	})
+
+	items := []string{}
+	for _, field := range fields {
+		if item := strings.TrimSpace(field); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// NormalizeSpecQuantity reads the first number of the value in the unit of the key, so
+// "1 TB" for capacity_gb gives "1000", "32 cm" for length_mm gives "320" and "up to 2x120mm"
+// for radiator_support gives "240". Keys without a unit suffix, such as slot counts, keep
+// the plain number.
+func NormalizeSpecQuantity(key, value string) (string, error) {
+	value = strings.ReplaceAll(value, ",", "")
+
+	var units map[string]float64
+	switch {
+	case strings.HasSuffix(key, "_mm") || key == "radiator_support":
+		units = specLengthUnits
+	case strings.HasSuffix(key, "_gb"):
+		units = specCapacityUnits
+	}
+
+	// A quantity with a unit such as "2x120mm" is the count times the size
+	count := 1.0
+	match := specQuantityPattern.FindStringSubmatch(value)
+	if multiplied := specMultipliedPattern.FindStringSubmatch(value); multiplied != nil && units != nil {
+		count, _ = strconv.ParseFloat(multiplied[1], 64)
+		match = multiplied[1:]
+	}
+	if match == nil {
+		return "", fmt.Errorf("spec %s must be a number, got %q", key, value)
+	}
+
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return "", fmt.Errorf("spec %s must be a number, got %q", key, value)
+	}
+	number *= count
+
+	if units == nil {
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	}
+
+	factor, known := units[strings.ToLower(match[2])]
+	if !known {
+		return "", fmt.Errorf("spec %s has an unknown unit %q", key, match[2])
+	}
+	return strconv.FormatFloat(math.Round(number*factor*100)/100, 'f', -1, 64), nil
+}
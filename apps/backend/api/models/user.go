@@ -6,6 +6,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// User roles
+const (
+	RoleUser   = "user"
+	RoleAdmin  = "admin"
+	RoleVendor = "vendor"
+)
+
 type User struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"user_id"`
 	Email     string    `gorm:"type:varchar(255);uniqueIndex" json:"email"`
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"pc-builder/backend/api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BenchmarkRepository struct {
+	db *gorm.DB
+}
+
+func NewBenchmarkRepository(db *gorm.DB) *BenchmarkRepository {
+	return &BenchmarkRepository{db: db}
+}
+
+type BenchmarkFilter struct {
+	ComponentID string
+	Name        string
+}
+
+func (r *BenchmarkRepository) GetBenchmarks(filter BenchmarkFilter) ([]models.Benchmark, error) {
+	query := r.db.Model(&models.Benchmark{})
+	if filter.ComponentID != "" {
+		query = query.Where("component_id = ?", filter.ComponentID)
+	}
+	if filter.Name != "" {
+		query = query.Where("name = ?", filter.Name)
+	}
+
+	var benchmarks []models.Benchmark
+	err := query.Order("component_id, name, measured_at DESC").Find(&benchmarks).Error
+	return benchmarks, err
+}
+
+func (r *BenchmarkRepository) GetBenchmarkByID(id uint) (*models.Benchmark, error) {
+	var benchmark models.Benchmark
+	err := r.db.Where("id = ?", id).First(&benchmark).Error
+	if err != nil {
+		return nil, err
+	}
+	return &benchmark, nil
+}
+
+func (r *BenchmarkRepository) CreateBenchmark(benchmark *models.Benchmark) error {
+	return r.db.Create(benchmark).Error
+}
+
+// UpsertBenchmark replaces the score of an existing component, name and source triple
+func (r *BenchmarkRepository) UpsertBenchmark(benchmark *models.Benchmark) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "component_id"}, {Name: "name"}, {Name: "source"}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "measured_at", "updated_at"}),
+	}).Create(benchmark).Error
+}
+
+func (r *BenchmarkRepository) UpdateBenchmark(benchmark *models.Benchmark, updates map[string]interface{}) error {
+	return r.db.Model(benchmark).Updates(updates).Error
+}
+
+func (r *BenchmarkRepository) DeleteBenchmark(id uint) error {
+	return r.db.Delete(&models.Benchmark{}, "id = ?", id).Error
+}
+
+// loadBenchmarkScores returns the scores of the components keyed by component ID, latest first
+func loadBenchmarkScores(db *gorm.DB, componentIDs []string) (map[string][]models.BenchmarkScore, error) {
+	var benchmarks []models.Benchmark
+	err := db.Where("component_id IN ?", componentIDs).
+		Order("name, measured_at DESC").
+		Find(&benchmarks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string][]models.BenchmarkScore)
+	for _, benchmark := range benchmarks {
+		scores[benchmark.ComponentID] = append(scores[benchmark.ComponentID], models.BenchmarkScore{
+			Name:       benchmark.Name,
+			Score:      benchmark.Score,
+			Source:     benchmark.Source,
+			MeasuredAt: benchmark.MeasuredAt,
+		})
+	}
+
+	return scores, nil
+}
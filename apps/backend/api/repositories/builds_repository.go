@@ -0,0 +1,500 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	shareSlugLength   = 8
+	shareSlugAttempts = 5
+
+	// MaxSlotQuantity bounds the quantity of one build slot
+	MaxSlotQuantity = 99
+)
+
+type BuildRepository struct {
+	db         *gorm.DB
+	components *ComponentRepository
+}
+
+func NewBuildRepository(db *gorm.DB) *BuildRepository {
+	return &BuildRepository{
+		db:         db,
+		components: NewComponentRepository(db),
+	}
+}
+
+// BuildSlot is one requested component of a build. An omitted quantity means 1, an explicit
+// one has to be between 1 and MaxSlotQuantity.
+type BuildSlot struct {
+	ComponentID string `json:"component_id" binding:"required"`
+	Quantity    int    `json:"quantity" binding:"min=1,max=99"`
+}
+
+// UnmarshalJSON defaults the quantity before decoding, so only an omitted quantity becomes 1
+// and an explicit 0 is left for the binding to reject
+func (s *BuildSlot) UnmarshalJSON(data []byte) error {
+	type rawSlot BuildSlot
+	slot := rawSlot{Quantity: 1}
+	if err := json.Unmarshal(data, &slot); err != nil {
+		return err
+	}
+
+	*s = BuildSlot(slot)
+	return nil
+}
+
+type BuildListResponse struct {
+	Builds     []models.BuildWithTotals `json:"builds"`
+	Pagination PaginationMeta           `json:"pagination"`
+}
+
+type SharedBuildComponent struct {
+	Quantity  int                           `json:"quantity"`
+	Component models.ComponentWithRelations `json:"component"`
+}
+
+// SharedBuildResponse is the anonymous view of a public build, without the owner
+type SharedBuildResponse struct {
+	ID         uuid.UUID              `json:"id"`
+	Name       string                 `json:"name"`
+	Notes      string                 `json:"notes"`
+	ShareSlug  string                 `json:"share_slug"`
+	ViewCount  int64                  `json:"view_count"`
+	ForkCount  int64                  `json:"fork_count"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+	Components []SharedBuildComponent `json:"components"`
+	TotalItems int                    `json:"total_items"`
+	Totals     models.Price           `json:"totals"`
+}
+
+// NormalizeBuildSlots merges duplicated components and defaults missing quantities to 1
+func NormalizeBuildSlots(slots []BuildSlot) []BuildSlot {
+	normalized := []BuildSlot{}
+	indexByID := make(map[string]int)
+
+	for _, slot := range slots {
+		quantity := slot.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+
+		if i, exists := indexByID[slot.ComponentID]; exists {
+			normalized[i].Quantity += quantity
+			continue
+		}
+
+		indexByID[slot.ComponentID] = len(normalized)
+		normalized = append(normalized, BuildSlot{ComponentID: slot.ComponentID, Quantity: quantity})
+	}
+
+	return normalized
+}
+
+// FindMissingComponents returns the IDs that do not match a live component
+func (r *BuildRepository) FindMissingComponents(componentIDs []string) ([]string, error) {
+	var found []string
+	err := r.db.Model(&models.Component{}).
+		Where("components.id IN ? AND "+liveComponentSQL, componentIDs).
+		Pluck("id", &found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	foundSet := make(map[string]bool)
+	for _, id := range found {
+		foundSet[id] = true
+	}
+
+	missing := []string{}
+	for _, id := range componentIDs {
+		if !foundSet[id] {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing, nil
+}
+
+func (r *BuildRepository) CreateBuild(build *models.Build, slots []BuildSlot) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit("Components").Create(build).Error
+		if err != nil {
+			return err
+		}
+
+		return createBuildComponents(tx, build.ID, slots)
+	})
+}
+
+func (r *BuildRepository) GetBuildsByUser(userID uuid.UUID, pagination PaginationParams) (*BuildListResponse, error) {
+	var totalRecords int64
+	err := r.db.Model(&models.Build{}).Where("user_id = ?", userID).Count(&totalRecords).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var builds []models.Build
+	err = r.preloadBuildComponents(r.db).
+		Where("user_id = ?", userID).
+		Order("updated_at DESC").
+		Offset((pagination.Page - 1) * pagination.PageSize).
+		Limit(pagination.PageSize).
+		Find(&builds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := deriveSlotPrices(r.db, builds); err != nil {
+		return nil, err
+	}
+
+	results := []models.BuildWithTotals{}
+	for _, build := range builds {
+		results = append(results, withTotals(build))
+	}
+
+	if err := r.attachParents(results); err != nil {
+		return nil, err
+	}
+
+	totalPages := int((totalRecords + int64(pagination.PageSize) - 1) / int64(pagination.PageSize))
+
+	return &BuildListResponse{
+		Builds: results,
+		Pagination: PaginationMeta{
+			CurrentPage:  pagination.Page,
+			PageSize:     pagination.PageSize,
+			TotalPages:   totalPages,
+			TotalRecords: totalRecords,
+		},
+	}, nil
+}
+
+func (r *BuildRepository) GetBuildByID(id uuid.UUID) (*models.BuildWithTotals, error) {
+	var build models.Build
+	err := r.preloadBuildComponents(r.db).Where("id = ?", id).First(&build).Error
+	if err != nil {
+		return nil, err
+	}
+
+	builds := []models.Build{build}
+	if err := deriveSlotPrices(r.db, builds); err != nil {
+		return nil, err
+	}
+
+	results := []models.BuildWithTotals{withTotals(build)}
+	if err := r.attachParents(results); err != nil {
+		return nil, err
+	}
+
+	return &results[0], nil
+}
+
+// UpdateBuild applies field updates and, when slots is not nil, replaces every slot of the build
+func (r *BuildRepository) UpdateBuild(build *models.Build, updates map[string]interface{}, slots []BuildSlot) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			err := tx.Model(build).Updates(updates).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if slots == nil {
+			return nil
+		}
+
+		err := tx.Where("build_id = ?", build.ID).Delete(&models.BuildComponent{}).Error
+		if err != nil {
+			return err
+		}
+
+		// Touch the build so list ordering reflects slot changes
+		err = tx.Model(build).Update("updated_at", gorm.Expr("NOW()")).Error
+		if err != nil {
+			return err
+		}
+
+		return createBuildComponents(tx, build.ID, slots)
+	})
+}
+
+func (r *BuildRepository) DeleteBuild(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("build_id = ?", id).Delete(&models.BuildComponent{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.Build{}, "id = ?", id).Error
+	})
+}
+
+// ForkBuild copies the source build and its slots into the user's account and counts
+// the fork on the source
+func (r *BuildRepository) ForkBuild(source *models.Build, userID uuid.UUID, name string) (*models.Build, error) {
+	if name == "" {
+		name = source.Name
+	}
+
+	fork := &models.Build{
+		UserID:        userID,
+		Name:          name,
+		Notes:         source.Notes,
+		ParentBuildID: &source.ID,
+	}
+
+	slots := make([]BuildSlot, len(source.Components))
+	for i, slot := range source.Components {
+		slots[i] = BuildSlot{ComponentID: slot.ComponentID, Quantity: slot.Quantity}
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit("Components").Create(fork).Error
+		if err != nil {
+			return err
+		}
+
+		err = createBuildComponents(tx, fork.ID, slots)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(source).UpdateColumn("fork_count", gorm.Expr("fork_count + 1")).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fork, nil
+}
+
+// ShareBuild makes the build public, keeping its slug when it already has one
+func (r *BuildRepository) ShareBuild(build *models.Build) error {
+	if build.ShareSlug != nil {
+		build.IsPublic = true
+		return r.db.Model(build).Update("is_public", true).Error
+	}
+
+	for attempt := 0; attempt < shareSlugAttempts; attempt++ {
+		slug, err := utils.GenerateSlug(shareSlugLength)
+		if err != nil {
+			return err
+		}
+
+		err = r.db.Model(build).Updates(map[string]interface{}{
+			"is_public":  true,
+			"share_slug": slug,
+		}).Error
+		if err == nil {
+			build.IsPublic = true
+			build.ShareSlug = &slug
+			return nil
+		}
+
+		if !strings.Contains(err.Error(), "duplicate key") {
+			return err
+		}
+	}
+
+	return errors.New("failed to generate a unique share slug")
+}
+
+// RevokeShare makes the build private and drops its slug so the old link stops working
+func (r *BuildRepository) RevokeShare(build *models.Build) error {
+	err := r.db.Model(build).Updates(map[string]interface{}{
+		"is_public":  false,
+		"share_slug": nil,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	build.IsPublic = false
+	build.ShareSlug = nil
+	return nil
+}
+
+// GetSharedBuild loads a public build by slug with resolved component details and
+// counts the view
+func (r *BuildRepository) GetSharedBuild(slug string) (*SharedBuildResponse, error) {
+	var build models.Build
+	err := r.db.
+		Preload("Components", func(db *gorm.DB) *gorm.DB {
+			return db.Order("build_components.id ASC")
+		}).
+		Where("share_slug = ? AND is_public = true", slug).
+		First(&build).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Model(&build).UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(build.Components))
+	for i, slot := range build.Components {
+		ids[i] = slot.ComponentID
+	}
+
+	components, err := r.components.GetComponentsByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]models.ComponentWithRelations)
+	for _, component := range components {
+		byID[component.ID] = component
+	}
+
+	response := &SharedBuildResponse{
+		ID:         build.ID,
+		Name:       build.Name,
+		Notes:      build.Notes,
+		ShareSlug:  slug,
+		ViewCount:  build.ViewCount + 1,
+		ForkCount:  build.ForkCount,
+		CreatedAt:  build.CreatedAt,
+		UpdatedAt:  build.UpdatedAt,
+		Components: []SharedBuildComponent{},
+	}
+
+	// Inactive components are left out of both the list and the totals
+	priced := []models.BuildComponent{}
+	for _, slot := range build.Components {
+		component, exists := byID[slot.ComponentID]
+		if !exists {
+			continue
+		}
+
+		response.Components = append(response.Components, SharedBuildComponent{
+			Quantity:  slot.Quantity,
+			Component: component,
+		})
+		response.TotalItems += slot.Quantity
+		priced = append(priced, models.BuildComponent{Quantity: slot.Quantity, Component: &component.Component})
+	}
+	response.Totals = CalculateBuildTotals(priced)
+
+	return response, nil
+}
+
+func (r *BuildRepository) preloadBuildComponents(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Components", func(db *gorm.DB) *gorm.DB {
+			return db.Order("build_components.id ASC")
+		}).
+		Preload("Components.Component").
+		Preload("Components.Component.Category")
+}
+
+// attachParents fills ForkedFrom on forks. Private parents of other users only expose their ID.
+func (r *BuildRepository) attachParents(builds []models.BuildWithTotals) error {
+	parentIDs := []uuid.UUID{}
+	for _, build := range builds {
+		if build.ParentBuildID != nil {
+			parentIDs = append(parentIDs, *build.ParentBuildID)
+		}
+	}
+
+	if len(parentIDs) == 0 {
+		return nil
+	}
+
+	var parents []models.Build
+	err := r.db.Select("id", "user_id", "name", "is_public", "share_slug").
+		Where("id IN ?", parentIDs).
+		Find(&parents).Error
+	if err != nil {
+		return err
+	}
+
+	parentByID := make(map[uuid.UUID]models.Build)
+	for _, parent := range parents {
+		parentByID[parent.ID] = parent
+	}
+
+	for i := range builds {
+		if builds[i].ParentBuildID == nil {
+			continue
+		}
+
+		summary := &models.BuildParent{ID: *builds[i].ParentBuildID}
+		if parent, exists := parentByID[summary.ID]; exists {
+			if parent.IsPublic {
+				summary.Name = parent.Name
+				summary.ShareSlug = parent.ShareSlug
+			} else if parent.UserID == builds[i].UserID {
+				summary.Name = parent.Name
+			}
+		}
+		builds[i].ForkedFrom = summary
+	}
+
+	return nil
+}
+
+func createBuildComponents(tx *gorm.DB, buildID uuid.UUID, slots []BuildSlot) error {
+	for _, slot := range slots {
+		buildComponent := models.BuildComponent{
+			BuildID:     buildID,
+			ComponentID: slot.ComponentID,
+			Quantity:    slot.Quantity,
+		}
+		err := tx.Create(&buildComponent).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func withTotals(build models.Build) models.BuildWithTotals {
+	totalItems := 0
+	for _, slot := range build.Components {
+		totalItems += slot.Quantity
+	}
+
+	return models.BuildWithTotals{
+		Build:      build,
+		TotalItems: totalItems,
+		Totals:     CalculateBuildTotals(build.Components),
+	}
+}
+
+// CalculateBuildTotals sums quantity * amount per currency across the build slots
+func CalculateBuildTotals(slots []models.BuildComponent) models.Price {
+	totals := models.Price{}
+	indexByCurrency := make(map[string]int)
+
+	for _, slot := range slots {
+		if slot.Component == nil {
+			continue
+		}
+
+		for _, item := range models.ParsePrice(slot.Component.Price) {
+			i, exists := indexByCurrency[item.Currency]
+			if !exists {
+				i = len(totals)
+				indexByCurrency[item.Currency] = i
+				totals = append(totals, models.PriceItem{Currency: item.Currency, Symbol: item.Symbol})
+			}
+			totals[i].Amount += item.Amount * float64(slot.Quantity)
+		}
+	}
+
+	return totals
+}
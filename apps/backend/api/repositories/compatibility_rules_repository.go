@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"pc-builder/backend/api/models"
+
+	"gorm.io/gorm"
+)
+
+type CompatibilityRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewCompatibilityRuleRepository(db *gorm.DB) *CompatibilityRuleRepository {
+	return &CompatibilityRuleRepository{db: db}
+}
+
+func (r *CompatibilityRuleRepository) GetAllRules() ([]models.CompatibilityRule, error) {
+	var rules []models.CompatibilityRule
+	err := r.db.Order("source_category_id, id").Find(&rules).Error
+	return rules, err
+}
+
+func (r *CompatibilityRuleRepository) GetActiveRules() ([]models.CompatibilityRule, error) {
+	var rules []models.CompatibilityRule
+	err := r.db.Where("is_active = true").Order("id").Find(&rules).Error
+	return rules, err
+}
+
+func (r *CompatibilityRuleRepository) GetRuleByID(id string) (*models.CompatibilityRule, error) {
+	var rule models.CompatibilityRule
+	err := r.db.Where("id = ?", id).First(&rule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *CompatibilityRuleRepository) CreateRule(rule *models.CompatibilityRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *CompatibilityRuleRepository) UpdateRule(rule *models.CompatibilityRule, updates map[string]interface{}) error {
+	return r.db.Model(rule).Updates(updates).Error
+}
+
+func (r *CompatibilityRuleRepository) DeleteRule(id string) error {
+	return r.db.Delete(&models.CompatibilityRule{}, "id = ?", id).Error
+}
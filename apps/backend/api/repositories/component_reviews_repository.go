@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"pc-builder/backend/api/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNothingToReview is returned when a component is neither waiting to be published nor
+// holding pending changes
+var ErrNothingToReview = errors.New("component has nothing to review")
+
+// ReviewQueueResponse is one page of the review queue, longest waiting first
+type ReviewQueueResponse struct {
+	Components []models.Component `json:"components"`
+	Pagination PaginationMeta     `json:"pagination"`
+}
+
+// ApprovalResult tells what an approval did. Applied is set when pending changes of a
+// published component were applied, with the price it had before.
+type ApprovalResult struct {
+	Published bool
+	Applied   *models.ComponentSnapshot
+	Previous  models.Price
+}
+
+type ComponentReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewComponentReviewRepository(db *gorm.DB) *ComponentReviewRepository {
+	return &ComponentReviewRepository{db: db}
+}
+
+// reviewQueue scopes components waiting for a first review or holding pending changes
+func reviewQueue(db *gorm.DB) *gorm.DB {
+	return db.Where("status = ? OR pending_changes IS NOT NULL", models.ComponentStatusPendingReview)
+}
+
+func (r *ComponentReviewRepository) GetReviewQueue(pagination PaginationParams) (*ReviewQueueResponse, error) {
+	var totalRecords int64
+	err := r.db.Model(&models.Component{}).Scopes(reviewQueue).Count(&totalRecords).Error
+	if err != nil {
+		return nil, err
+	}
+
+	components := []models.Component{}
+	err = r.db.Scopes(reviewQueue).
+		Order("submitted_at ASC").
+		Offset((pagination.Page - 1) * pagination.PageSize).
+		Limit(pagination.PageSize).
+		Find(&components).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int((totalRecords + int64(pagination.PageSize) - 1) / int64(pagination.PageSize))
+
+	return &ReviewQueueResponse{
+		Components: components,
+		Pagination: PaginationMeta{
+			CurrentPage:  pagination.Page,
+			PageSize:     pagination.PageSize,
+			TotalPages:   totalPages,
+			TotalRecords: totalRecords,
+		},
+	}, nil
+}
+
+// SubmitForReview moves a draft or rejected component to the review queue
+func (r *ComponentReviewRepository) SubmitForReview(component *models.Component) error {
+	return r.db.Model(component).Updates(map[string]interface{}{
+		"status":       models.ComponentStatusPendingReview,
+		"submitted_at": time.Now(),
+		"review_note":  "",
+	}).Error
+}
+
+// StageChanges keeps the changed fields of a published component for review, replacing the
+// ones already waiting
+func (r *ComponentReviewRepository) StageChanges(component *models.Component, changes models.ComponentChanges) error {
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Model(component).Updates(map[string]interface{}{
+		"pending_changes": changesJSON,
+		"submitted_at":    time.Now(),
+		"review_note":     "",
+	}).Error
+}
+
+// Approve publishes a component waiting for review, or applies the pending changes of a
+// published one to its live state and records them as a revision
+func (r *ComponentReviewRepository) Approve(componentID string, reviewerID *uuid.UUID) (*ApprovalResult, error) {
+	result := &ApprovalResult{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var component models.Component
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", componentID).First(&component).Error
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"review_note":     "",
+			"reviewed_by":     reviewerID,
+			"reviewed_at":     time.Now(),
+			"pending_changes": nil,
+		}
+
+		switch {
+		case component.Status == models.ComponentStatusPendingReview:
+			updates["status"] = models.ComponentStatusPublished
+			result.Published = true
+		case len(component.PendingChanges) > 0:
+			var changes models.ComponentChanges
+			if err := json.Unmarshal(component.PendingChanges, &changes); err != nil {
+				return err
+			}
+
+			// The changes go on top of the live component, so edits made since they were
+			// staged are kept for the fields the vendor left alone
+			live, err := loadSnapshot(tx, &component)
+			if err != nil {
+				return err
+			}
+			snapshot := changes.Apply(live)
+
+			result.Previous, err = applySnapshot(tx, &component, snapshot, reviewerID)
+			if err != nil {
+				return err
+			}
+			result.Applied = &snapshot
+		default:
+			return ErrNothingToReview
+		}
+
+		if err := tx.Model(&component).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		if result.Applied == nil {
+			return nil
+		}
+		return RecordRevision(tx, componentID, models.RevisionActionApprove, reviewerID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Reject sends a component waiting for review back to its vendor, or drops the pending
+// changes of a published one, with the reason in review_note
+func (r *ComponentReviewRepository) Reject(componentID string, reviewerID *uuid.UUID, reason string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var component models.Component
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", componentID).First(&component).Error
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"review_note":     reason,
+			"reviewed_by":     reviewerID,
+			"reviewed_at":     time.Now(),
+			"pending_changes": nil,
+		}
+
+		switch {
+		case component.Status == models.ComponentStatusPendingReview:
+			updates["status"] = models.ComponentStatusRejected
+		case len(component.PendingChanges) > 0:
+			// The published component stays live as it is
+		default:
+			return ErrNothingToReview
+		}
+
+		return tx.Model(&component).Updates(updates).Error
+	})
+}
@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"encoding/json"
+	"pc-builder/backend/api/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ComponentRevisionResponse is one page of the revisions of a component, newest first
+type ComponentRevisionResponse struct {
+	Revisions  []models.ComponentRevision `json:"revisions"`
+	Pagination PaginationMeta             `json:"pagination"`
+}
+
+type ComponentRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewComponentRevisionRepository(db *gorm.DB) *ComponentRevisionRepository {
+	return &ComponentRevisionRepository{db: db}
+}
+
+func (r *ComponentRevisionRepository) GetRevisions(componentID string, pagination PaginationParams) (*ComponentRevisionResponse, error) {
+	var totalRecords int64
+	err := r.db.Model(&models.ComponentRevision{}).Where("component_id = ?", componentID).Count(&totalRecords).Error
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []models.ComponentRevision{}
+	err = r.db.Where("component_id = ?", componentID).
+		Order("revision DESC").
+		Offset((pagination.Page - 1) * pagination.PageSize).
+		Limit(pagination.PageSize).
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int((totalRecords + int64(pagination.PageSize) - 1) / int64(pagination.PageSize))
+
+	return &ComponentRevisionResponse{
+		Revisions: revisions,
+		Pagination: PaginationMeta{
+			CurrentPage:  pagination.Page,
+			PageSize:     pagination.PageSize,
+			TotalPages:   totalPages,
+			TotalRecords: totalRecords,
+		},
+	}, nil
+}
+
+func (r *ComponentRevisionRepository) GetRevision(componentID string, revision int) (*models.ComponentRevision, error) {
+	var componentRevision models.ComponentRevision
+	err := r.db.Where("component_id = ? AND revision = ?", componentID, revision).First(&componentRevision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &componentRevision, nil
+}
+
+// RollbackComponent restores the core fields, brands, specs, price and images of a revision
+// and records the result as a new revision, so the history is never rewritten. It returns
+// the price the component had before.
+func (r *ComponentRevisionRepository) RollbackComponent(componentID string, revision int, userID *uuid.UUID) (models.Price, *models.ComponentSnapshot, error) {
+	var previous models.Price
+	var restored models.ComponentSnapshot
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var target models.ComponentRevision
+		err := tx.Where("component_id = ? AND revision = ?", componentID, revision).First(&target).Error
+		if err != nil {
+			return err
+		}
+
+		restored, err = target.ParseSnapshot()
+		if err != nil {
+			return err
+		}
+
+		var component models.Component
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", componentID).First(&component).Error
+		if err != nil {
+			return err
+		}
+
+		previous, err = applySnapshot(tx, &component, restored, userID)
+		if err != nil {
+			return err
+		}
+
+		return RecordRevision(tx, componentID, models.RevisionActionRollback, userID)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return previous, &restored, nil
+}
+
+// RecordRevision snapshots the component as the transaction sees it and stores it as the
+// next revision. The component row is locked so concurrent changes get distinct numbers.
+func RecordRevision(tx *gorm.DB, componentID string, action string, userID *uuid.UUID) error {
+	var component models.Component
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", componentID).First(&component).Error
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := loadSnapshot(tx, &component)
+	if err != nil {
+		return err
+	}
+
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	var lastRevision int
+	err = tx.Model(&models.ComponentRevision{}).
+		Where("component_id = ?", componentID).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&lastRevision).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&models.ComponentRevision{
+		ComponentID: componentID,
+		Revision:    lastRevision + 1,
+		Action:      action,
+		Snapshot:    snapshotJSON,
+		CreatedBy:   userID,
+	}).Error
+}
+
+// loadSnapshot adds the brands and specs of the component to its core fields
+func loadSnapshot(tx *gorm.DB, component *models.Component) (models.ComponentSnapshot, error) {
+	var brands []models.ComponentBrands
+	err := tx.Where("component_id = ?", component.ID).Order("is_primary DESC, brand_id").Find(&brands).Error
+	if err != nil {
+		return models.ComponentSnapshot{}, err
+	}
+
+	var specs []models.ComponentSpec
+	err = tx.Where("component_id = ?", component.ID).Find(&specs).Error
+	if err != nil {
+		return models.ComponentSnapshot{}, err
+	}
+
+	snapshot := models.ComponentSnapshot{
+		Name:       component.Name,
+		CategoryID: component.CategoryID,
+		Models:     component.Models,
+		IsActive:   component.IsActive,
+		InStock:    component.InStock,
+		Price:      models.ParsePrice(component.Price),
+		ImageURL:   models.ImageURL{},
+		Brands:     make([]models.SnapshotBrand, 0, len(brands)),
+		Specs:      make(map[string]string, len(specs)),
+	}
+	if err := json.Unmarshal(component.ImageURL, &snapshot.ImageURL); err != nil {
+		snapshot.ImageURL = models.ImageURL{}
+	}
+	for _, brand := range brands {
+		snapshot.Brands = append(snapshot.Brands, models.SnapshotBrand{BrandID: brand.BrandID, IsPrimary: brand.IsPrimary})
+	}
+	for _, spec := range specs {
+		snapshot.Specs[spec.SpecKey] = spec.SpecValue
+	}
+
+	return snapshot, nil
+}
+
+// applySnapshot writes the core fields, brands, specs, price and images of the snapshot to
+// the component and records the price changes. It returns the price the component had before.
+func applySnapshot(tx *gorm.DB, component *models.Component, snapshot models.ComponentSnapshot, userID *uuid.UUID) (models.Price, error) {
+	previous := models.ParsePrice(component.Price)
+
+	if snapshot.Price == nil {
+		snapshot.Price = models.Price{}
+	}
+	if snapshot.ImageURL == nil {
+		snapshot.ImageURL = models.ImageURL{}
+	}
+
+	priceJSON, err := json.Marshal(snapshot.Price)
+	if err != nil {
+		return nil, err
+	}
+	imageJSON, err := json.Marshal(snapshot.ImageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Model(component).Updates(map[string]interface{}{
+		"name":        snapshot.Name,
+		"category_id": snapshot.CategoryID,
+		"models":      snapshot.Models,
+		"is_active":   snapshot.IsActive,
+		"in_stock":    snapshot.InStock,
+		"price":       priceJSON,
+		"image_url":   imageJSON,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = RecordPriceChanges(tx, component.ID, previous, snapshot.Price, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Where("component_id = ?", component.ID).Delete(&models.ComponentBrands{}).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, brand := range snapshot.Brands {
+		componentBrand := models.ComponentBrands{
+			ComponentID: component.ID,
+			BrandID:     brand.BrandID,
+			IsPrimary:   brand.IsPrimary,
+		}
+		if err := tx.Create(&componentBrand).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Where("component_id = ?", component.ID).Delete(&models.ComponentSpec{}).Error
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range snapshot.Specs {
+		spec := models.ComponentSpec{
+			ComponentID:  component.ID,
+			SpecKey:      key,
+			SpecValue:    value,
+			SpecType:     GetSpecType(key),
+			IsFilterable: isFilterableSpec(key),
+		}
+		if err := tx.Create(&spec).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return previous, nil
+}
@@ -3,9 +3,13 @@ package repositories
 import (
 	"fmt"
 	"pc-builder/backend/api/models"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ComponentRepository struct {
@@ -27,6 +31,9 @@ type ComponentFilter struct {
 	SortOrder        string            `form:"sort_order"`
 	Currency         string            `form:"currency"`
 	Specs            map[string]string `form:"-"`
+	Benchmark        string            `form:"benchmark" json:"benchmark,omitempty"` // Used by the price_per_performance sort
+	CompatibleWith   string            `form:"compatible_with" json:"compatible_with,omitempty"`
+	ExcludedIDs      []string          `form:"-" json:"-"` // Resolved from CompatibleWith
 }
 
 type PaginationParams struct {
@@ -113,26 +120,28 @@ func (r *ComponentRepository) GetComponentsWithFilters(filters ComponentFilter,
 			components.image_url,
 			components.is_active,
 			components.in_stock,
+			components.created_by,
+			components.status,
 			components.created_at,
 			components.updated_at,
 			categories.name as category_name,
 			categories.display_name as category_display,
 			(SELECT brands.name FROM brands
 			 JOIN component_brands ON brands.id = component_brands.brand_id
-			 WHERE component_brands.component_id = components.id LIMIT 1) as brand_name,
+			 WHERE component_brands.component_id = components.id AND brands.deleted_at IS NULL LIMIT 1) as brand_name,
 			(SELECT brands.display_name FROM brands
 			 JOIN component_brands ON brands.id = component_brands.brand_id
-			 WHERE component_brands.component_id = components.id LIMIT 1) as brand_display
+			 WHERE component_brands.component_id = components.id AND brands.deleted_at IS NULL LIMIT 1) as brand_display
 		`).
-		Joins("JOIN categories ON components.category_id = categories.id").
-		Where("components.is_active = true")
+		Joins("JOIN categories ON components.category_id = categories.id AND categories.deleted_at IS NULL").
+		Where(liveComponentSQL)
 
 	query = r.applyFilters(query, filters)
 
 	var totalRecords int64
 	countQuery := r.db.Model(&models.Component{}).
-		Joins("JOIN categories ON components.category_id = categories.id").
-		Where("components.is_active = true")
+		Joins("JOIN categories ON components.category_id = categories.id AND categories.deleted_at IS NULL").
+		Where(liveComponentSQL)
 	countQuery = r.applyFilters(countQuery, filters)
 	err := countQuery.Count(&totalRecords).Error
 	if err != nil {
@@ -176,6 +185,21 @@ func (r *ComponentRepository) GetComponentsWithFilters(filters ComponentFilter,
 		components = append(components, comp)
 	}
 
+	err = r.attachBenchmarks(components)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.attachDerivedPrices(components)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.attachOffers(components)
+	if err != nil {
+		return nil, err
+	}
+
 	totalPages := int((totalRecords + int64(pagination.PageSize) - 1) / int64(pagination.PageSize))
 
 	summary := r.getComponentSummary(filters)
@@ -205,14 +229,16 @@ func (r *ComponentRepository) GetComponentByID(id string) (*models.ComponentWith
 			components.image_url,
 			components.is_active,
 			components.in_stock,
+			components.created_by,
+			components.status,
 			components.created_at,
 			components.updated_at,
 			categories.name as category_name,
 			categories.display_name as category_display
 		`).
 		Table("components").
-		Joins("JOIN categories ON components.category_id = categories.id").
-		Where("components.id = ? AND components.is_active = true", id).
+		Joins("JOIN categories ON components.category_id = categories.id AND categories.deleted_at IS NULL").
+		Where("components.id = ? AND "+liveComponentSQL, id).
 		First(&component).Error
 
 	if err != nil {
@@ -231,7 +257,322 @@ func (r *ComponentRepository) GetComponentByID(id string) (*models.ComponentWith
 		return nil, err
 	}
 
-	return &component, nil
+	// Load benchmark scores
+	components := []models.ComponentWithRelations{component}
+	err = r.attachBenchmarks(components)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.attachDerivedPrices(components)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.attachOffers(components)
+	if err != nil {
+		return nil, err
+	}
+
+	return &components[0], nil
+}
+
+// GetComponentsByIDs loads active components with their relations, keeping the order of ids
+func (r *ComponentRepository) GetComponentsByIDs(ids []string) ([]models.ComponentWithRelations, error) {
+	results, err := r.findComponentsWithRelations(func(query *gorm.DB) *gorm.DB {
+		return query.Where("components.id IN ?", ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]models.ComponentWithRelations)
+	for _, component := range results {
+		byID[component.ID] = component
+	}
+
+	components := []models.ComponentWithRelations{}
+	for _, id := range ids {
+		if component, exists := byID[id]; exists {
+			components = append(components, component)
+		}
+	}
+
+	return components, nil
+}
+
+// VendorComponentResponse is one page of the catalog a vendor created
+type VendorComponentResponse struct {
+	Components []models.ComponentWithRelations `json:"components"`
+	Pagination PaginationMeta                  `json:"pagination"`
+}
+
+// GetComponentsByCreator lists the components created by the user, inactive ones included,
+// most recently updated first
+func (r *ComponentRepository) GetComponentsByCreator(userID uuid.UUID, pagination PaginationParams) (*VendorComponentResponse, error) {
+	var totalRecords int64
+	err := r.db.Model(&models.Component{}).
+		Joins("JOIN categories ON components.category_id = categories.id AND categories.deleted_at IS NULL").
+		Where("components.created_by = ?", userID).
+		Count(&totalRecords).Error
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := r.findAnyComponentsWithRelations(func(query *gorm.DB) *gorm.DB {
+		return query.Where("components.created_by = ? AND components.deleted_at IS NULL", userID).
+			Order("components.updated_at DESC").
+			Offset((pagination.Page - 1) * pagination.PageSize).
+			Limit(pagination.PageSize)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.attachReviewFields(components); err != nil {
+		return nil, err
+	}
+
+	totalPages := int((totalRecords + int64(pagination.PageSize) - 1) / int64(pagination.PageSize))
+
+	return &VendorComponentResponse{
+		Components: components,
+		Pagination: PaginationMeta{
+			CurrentPage:  pagination.Page,
+			PageSize:     pagination.PageSize,
+			TotalPages:   totalPages,
+			TotalRecords: totalRecords,
+		},
+	}, nil
+}
+
+// attachReviewFields loads the moderation state, which is only shown to the owner since it
+// holds rejection notes and unapproved changes
+func (r *ComponentRepository) attachReviewFields(components []models.ComponentWithRelations) error {
+	if len(components) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(components))
+	for i, component := range components {
+		ids[i] = component.ID
+	}
+
+	var reviews []models.Component
+	err := r.db.
+		Select("id, status, submitted_at, review_note, reviewed_by, reviewed_at, pending_changes").
+		Where("id IN ?", ids).
+		Find(&reviews).Error
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]models.ReviewFields, len(reviews))
+	for _, review := range reviews {
+		byID[review.ID] = review.ReviewFields
+	}
+
+	for i := range components {
+		components[i].ReviewFields = byID[components[i].ID]
+	}
+
+	return nil
+}
+
+// GetComponentsByCategories loads every active component, optionally limited to categories
+func (r *ComponentRepository) GetComponentsByCategories(categoryIDs []string) ([]models.ComponentWithRelations, error) {
+	return r.findComponentsWithRelations(func(query *gorm.DB) *gorm.DB {
+		if len(categoryIDs) > 0 {
+			query = query.Where("components.category_id IN ?", categoryIDs)
+		}
+		return query
+	})
+}
+
+// GetCompatibilityCandidates loads up to limit listed components matching the filters with
+// their specs only, which is all the compatibility rules read. Candidates come in ID order
+// after afterID so the caller can page through all of them.
+func (r *ComponentRepository) GetCompatibilityCandidates(filters ComponentFilter, afterID string, limit int) ([]models.ComponentWithRelations, error) {
+	filters.ExcludedIDs = nil
+
+	var components []models.ComponentWithRelations
+	query := r.db.
+		Select("components.id, components.name, components.category_id").
+		Table("components").
+		Joins("JOIN categories ON components.category_id = categories.id AND categories.deleted_at IS NULL").
+		Where(liveComponentSQL)
+	if afterID != "" {
+		query = query.Where("components.id > ?", afterID)
+	}
+	err := r.applyFilters(query, filters).
+		Order("components.id").
+		Limit(limit).
+		Find(&components).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(components) == 0 {
+		return components, nil
+	}
+
+	ids := make([]string, len(components))
+	indexByID := make(map[string]int, len(components))
+	for i := range components {
+		ids[i] = components[i].ID
+		indexByID[components[i].ID] = i
+		components[i].SpecsMap = make(map[string]string)
+	}
+
+	var specs []models.ComponentSpec
+	if err := r.db.Where("component_id IN ?", ids).Find(&specs).Error; err != nil {
+		return nil, err
+	}
+
+	for _, spec := range specs {
+		components[indexByID[spec.ComponentID]].SpecsMap[spec.SpecKey] = spec.SpecValue
+	}
+
+	return components, nil
+}
+
+// GetInStockComponents loads every active, in-stock component of the given categories
+func (r *ComponentRepository) GetInStockComponents(categoryIDs []string) ([]models.ComponentWithRelations, error) {
+	return r.findComponentsWithRelations(func(query *gorm.DB) *gorm.DB {
+		return query.Where("components.in_stock = true AND components.category_id IN ?", categoryIDs)
+	})
+}
+
+// findComponentsWithRelations runs the scoped query over active components
+func (r *ComponentRepository) findComponentsWithRelations(scope func(*gorm.DB) *gorm.DB) ([]models.ComponentWithRelations, error) {
+	return r.findAnyComponentsWithRelations(func(query *gorm.DB) *gorm.DB {
+		return scope(query.Where(liveComponentSQL))
+	})
+}
+
+// findAnyComponentsWithRelations runs the scoped query, inactive components included, and loads
+// brands and specs in one query each instead of once per component
+func (r *ComponentRepository) findAnyComponentsWithRelations(scope func(*gorm.DB) *gorm.DB) ([]models.ComponentWithRelations, error) {
+	var components []models.ComponentWithRelations
+	err := r.db.
+		Select(`
+			components.id,
+			components.name,
+			components.category_id,
+			components.models,
+			components.price,
+			components.image_url,
+			components.is_active,
+			components.in_stock,
+			components.created_by,
+			components.status,
+			components.created_at,
+			components.updated_at,
+			categories.name as category_name,
+			categories.display_name as category_display
+		`).
+		Table("components").
+		Joins("JOIN categories ON components.category_id = categories.id AND categories.deleted_at IS NULL").
+		Scopes(scope).
+		Find(&components).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(components) == 0 {
+		return components, nil
+	}
+
+	ids := make([]string, len(components))
+	for i, component := range components {
+		ids[i] = component.ID
+	}
+
+	var specs []models.ComponentSpec
+	err = r.db.Where("component_id IN ?", ids).Find(&specs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var brandAssociations []struct {
+		ComponentID  string
+		BrandName    string
+		BrandDisplay string
+		IsPrimary    bool
+	}
+	err = r.db.Table("component_brands").
+		Select("component_brands.component_id, brands.name as brand_name, brands.display_name as brand_display, component_brands.is_primary").
+		Joins("JOIN brands ON component_brands.brand_id = brands.id AND brands.deleted_at IS NULL").
+		Where("component_brands.component_id IN ?", ids).
+		Order("component_brands.is_primary DESC, brands.display_name ASC").
+		Find(&brandAssociations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	indexByID := make(map[string]int)
+	for i := range components {
+		indexByID[components[i].ID] = i
+		components[i].SpecsMap = make(map[string]string)
+		components[i].BrandNames = []string{}
+		components[i].BrandDisplays = []string{}
+	}
+
+	for _, spec := range specs {
+		components[indexByID[spec.ComponentID]].SpecsMap[spec.SpecKey] = spec.SpecValue
+	}
+
+	for _, assoc := range brandAssociations {
+		component := &components[indexByID[assoc.ComponentID]]
+		component.BrandNames = append(component.BrandNames, assoc.BrandName)
+		component.BrandDisplays = append(component.BrandDisplays, assoc.BrandDisplay)
+		if assoc.IsPrimary && component.PrimaryBrand == "" {
+			component.PrimaryBrand = assoc.BrandDisplay
+		}
+	}
+
+	err = r.attachBenchmarks(components)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.attachDerivedPrices(components)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.attachOffers(components)
+	if err != nil {
+		return nil, err
+	}
+
+	return components, nil
+}
+
+// attachBenchmarks loads the benchmark scores of every component in one query
+func (r *ComponentRepository) attachBenchmarks(components []models.ComponentWithRelations) error {
+	if len(components) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(components))
+	for i, component := range components {
+		ids[i] = component.ID
+	}
+
+	scores, err := loadBenchmarkScores(r.db, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range components {
+		components[i].Benchmarks = scores[components[i].ID]
+		if components[i].Benchmarks == nil {
+			components[i].Benchmarks = []models.BenchmarkScore{}
+		}
+	}
+
+	return nil
 }
 
 func (r *ComponentRepository) applySorting(query *gorm.DB, filters ComponentFilter) *gorm.DB {
@@ -249,27 +590,27 @@ func (r *ComponentRepository) applySorting(query *gorm.DB, filters ComponentFilt
 	case "name":
 		query = query.Order("components.name " + sortOrder)
 	case "price":
-		currency := filters.Currency
-		if currency == "" {
-			currency = "VND"
-		}
-		query = query.Order(fmt.Sprintf(`
-			(SELECT (price_item->>'amount')::numeric
-			 FROM jsonb_array_elements(components.price) as price_item
-			 WHERE price_item->>'currency' = '%s'
-			 LIMIT 1) %s
-		`, currency, sortOrder))
+		priceSQL, vars := priceInCurrencySQL(filterCurrency(filters))
+		query = query.Clauses(clause.OrderBy{
+			Expression: clause.Expr{
+				SQL:                priceSQL + " " + sortDirection(sortOrder) + " NULLS LAST",
+				Vars:               vars,
+				WithoutParentheses: true,
+			},
+		})
 	case "brand":
 		query = query.Order(fmt.Sprintf(`
 			(SELECT brands.display_name
 			 FROM component_brands
-			 JOIN brands ON component_brands.brand_id = brands.id
+			 JOIN brands ON component_brands.brand_id = brands.id AND brands.deleted_at IS NULL
 			 WHERE component_brands.component_id = components.id
 			 AND component_brands.is_primary = true
 			 LIMIT 1) %s
 		`, sortOrder))
 	case "category":
 		query = query.Order("categories.display_name " + sortOrder)
+	case "price_per_performance":
+		query = query.Clauses(pricePerPerformanceOrder(filters, sortOrder))
 	default:
 		query = query.Order("components." + sortBy + " " + sortOrder)
 	}
@@ -277,6 +618,41 @@ func (r *ComponentRepository) applySorting(query *gorm.DB, filters ComponentFilt
 	return query
 }
 
+// pricePerPerformanceOrder sorts by price in the filter currency divided by the latest score
+// of the requested benchmark, or the category default benchmark. Components without a
+// price or score go last.
+func pricePerPerformanceOrder(filters ComponentFilter, sortOrder string) clause.OrderBy {
+	priceSQL, vars := priceInCurrencySQL(filterCurrency(filters))
+
+	categories := make([]string, 0, len(models.DefaultBenchmarks))
+	for category := range models.DefaultBenchmarks {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	defaultBenchmark := "CASE components.category_id"
+	vars = append(vars, filters.Benchmark)
+	for _, category := range categories {
+		defaultBenchmark += " WHEN ? THEN ?"
+		vars = append(vars, category, models.DefaultBenchmarks[category])
+	}
+	defaultBenchmark += " END"
+
+	return clause.OrderBy{
+		Expression: clause.Expr{
+			SQL: priceSQL + ` /
+			NULLIF((SELECT benchmarks.score
+			 FROM benchmarks
+			 WHERE benchmarks.component_id = components.id
+			 AND benchmarks.name = COALESCE(NULLIF(?, ''), ` + defaultBenchmark + `)
+			 ORDER BY benchmarks.measured_at DESC
+			 LIMIT 1), 0) ` + sortDirection(sortOrder) + ` NULLS LAST`,
+			Vars:               vars,
+			WithoutParentheses: true,
+		},
+	}
+}
+
 func (r *ComponentRepository) loadComponentBrands(component *models.ComponentWithRelations) error {
 	var brandAssociations []struct {
 		BrandID      string
@@ -287,7 +663,7 @@ func (r *ComponentRepository) loadComponentBrands(component *models.ComponentWit
 
 	err := r.db.Table("component_brands").
 		Select("component_brands.brand_id, brands.name as brand_name, brands.display_name as brand_display, component_brands.is_primary").
-		Joins("JOIN brands ON component_brands.brand_id = brands.id").
+		Joins("JOIN brands ON component_brands.brand_id = brands.id AND brands.deleted_at IS NULL").
 		Where("component_brands.component_id = ?", component.ID).
 		Order("component_brands.is_primary DESC, brands.display_name ASC").
 		Find(&brandAssociations).Error
@@ -326,13 +702,20 @@ func (r *ComponentRepository) loadComponentSpecs(component *models.ComponentWith
 	return nil
 }
 
-func (r *ComponentRepository) CreateComponent(component *models.Component, brandAssociations []BrandAssociation, specs map[string]string) error {
+// CreateComponent stores the component with its brands and specs, records its initial
+// prices in the price history and writes its first revision
+func (r *ComponentRepository) CreateComponent(component *models.Component, brandAssociations []BrandAssociation, specs map[string]string, createdBy *uuid.UUID) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		err := tx.Create(component).Error
 		if err != nil {
 			return err
 		}
 
+		err = RecordPriceChanges(tx, component.ID, nil, models.ParsePrice(component.Price), createdBy)
+		if err != nil {
+			return err
+		}
+
 		for _, brandAssoc := range brandAssociations {
 			componentBrand := models.ComponentBrands{
 				ComponentID: component.ID,
@@ -350,7 +733,7 @@ func (r *ComponentRepository) CreateComponent(component *models.Component, brand
 				ComponentID:  component.ID,
 				SpecKey:      key,
 				SpecValue:    value,
-				SpecType:     "string",
+				SpecType:     GetSpecType(key),
 				IsFilterable: isFilterableSpec(key),
 			}
 			err := tx.Create(&spec).Error
@@ -359,7 +742,7 @@ func (r *ComponentRepository) CreateComponent(component *models.Component, brand
 			}
 		}
 
-		return nil
+		return RecordRevision(tx, component.ID, models.RevisionActionCreate, createdBy)
 	})
 }
 
@@ -371,8 +754,8 @@ func (r *ComponentRepository) getComponentSummary(filters ComponentFilter) Compo
 
 	categoryQuery := r.db.Table("components").
 		Select("categories.display_name as category_name, COUNT(*) as count").
-		Joins("JOIN categories ON components.category_id = categories.id").
-		Where("components.is_active = true").
+		Joins("JOIN categories ON components.category_id = categories.id AND categories.deleted_at IS NULL").
+		Where(liveComponentSQL).
 		Group("categories.display_name")
 
 	categoryQuery = r.applyFiltersForSummary(categoryQuery, filters)
@@ -393,9 +776,10 @@ func (r *ComponentRepository) getComponentSummary(filters ComponentFilter) Compo
 
 	brandQuery := r.db.Table("components").
 		Select("brands.display_name as brand_name, COUNT(DISTINCT components.id) as count").
+		Joins("JOIN categories ON components.category_id = categories.id AND categories.deleted_at IS NULL").
 		Joins("JOIN component_brands ON components.id = component_brands.component_id").
-		Joins("JOIN brands ON component_brands.brand_id = brands.id").
-		Where("components.is_active = true")
+		Joins("JOIN brands ON component_brands.brand_id = brands.id AND brands.deleted_at IS NULL").
+		Where(liveComponentSQL)
 
 	if filters.PrimaryBrandOnly {
 		brandQuery = brandQuery.Where("component_brands.is_primary = true")
@@ -410,23 +794,19 @@ func (r *ComponentRepository) getComponentSummary(filters ComponentFilter) Compo
 		byBrand[result.BrandName] = int(result.Count)
 	}
 
-	// Get price range
-	currency := filters.Currency
-	if currency == "" {
-		currency = "VND"
-	}
+	// Get price range, derived prices count for currencies the components do not store
+	currency := filterCurrency(filters)
 
 	var priceResult struct {
 		MinPrice float64 `json:"min_price"`
 		MaxPrice float64 `json:"max_price"`
 	}
 
-	// Create base query with filters applied
+	priceSQL, vars := priceInCurrencySQL(currency)
 	priceQuery := r.db.Table("components").
-		Select(`MIN((price_item->>'amount')::numeric) as min_price, MAX((price_item->>'amount')::numeric) as max_price`).
-		Joins(`CROSS JOIN LATERAL jsonb_array_elements(price) as price_item`).
-		Joins("JOIN categories ON components.category_id = categories.id").
-		Where(`price_item->>'currency' = ? AND components.is_active = true`, currency)
+		Select(fmt.Sprintf("MIN(%[1]s) as min_price, MAX(%[1]s) as max_price", priceSQL), append(vars, vars...)...).
+		Joins("JOIN categories ON components.category_id = categories.id AND categories.deleted_at IS NULL").
+		Where(liveComponentSQL)
 
 	// Apply filters to price query
 	priceQuery = r.applyFiltersForPriceRange(priceQuery, filters)
@@ -445,7 +825,22 @@ func (r *ComponentRepository) getComponentSummary(filters ComponentFilter) Compo
 }
 
 // Helper functions
-func getCurrencyForLang(lang string) string {
+func filterCurrency(filters ComponentFilter) string {
+	if filters.Currency == "" {
+		return models.BaseCurrency
+	}
+	return filters.Currency
+}
+
+func sortDirection(sortOrder string) string {
+	if strings.EqualFold(sortOrder, "asc") {
+		return "ASC"
+	}
+	return "DESC"
+}
+
+// CurrencyForLang is the currency shown by default for an Accept-Language value
+func CurrencyForLang(lang string) string {
 	currencies := map[string]string{
 		"en": "USD",
 		"vn": "VND",
@@ -453,7 +848,7 @@ func getCurrencyForLang(lang string) string {
 	if currency, exists := currencies[lang]; exists {
 		return currency
 	}
-	return "VND"
+	return models.BaseCurrency
 }
 
 func isFilterableSpec(key string) bool {
@@ -471,11 +866,81 @@ func isFilterableSpec(key string) bool {
 	return false
 }
 
+const (
+	SpecTypeString = "string"
+	SpecTypeNumber = "number"
+	SpecTypeList   = "list"
+)
+
+// Specs compared numerically by the build validation, stored as a number in the unit of the
+// key suffix
+var numericSpecs = []string{
+	"length_mm", "height_mm", "radiator_size_mm",
+	"gpu_max_length_mm", "cooler_max_height_mm",
+	"dimm_slots", "max_memory_gb", "m2_slots", "sata_ports",
+	"pcie_x16_slots", "pcie_x4_slots", "pcie_x1_slots",
+	"modules", "capacity_gb",
+}
+
+// Specs holding a list of sizes, such as the radiator lengths a case can mount
+var listSpecs = []string{"radiator_support"}
+
+func GetSpecType(key string) string {
+	if slices.Contains(numericSpecs, key) {
+		return SpecTypeNumber
+	}
+	if slices.Contains(listSpecs, key) {
+		return SpecTypeList
+	}
+	return SpecTypeString
+}
+
+// NormalizeSpecValue validates typed specs and converts their unit, so "32 cm" is stored
+// as "320", "1 TB" of capacity_gb as "1000" and "240mm / 360mm" as "240, 360"
+func NormalizeSpecValue(key, value string) (string, error) {
+	switch GetSpecType(key) {
+	case SpecTypeNumber:
+		return models.NormalizeSpecQuantity(key, value)
+	case SpecTypeList:
+		// Items that are not a size, such as "front" in "240mm / front", are left out, and a
+		// value without any size is kept as entered
+		items := []string{}
+		for _, item := range models.SplitSpecList(value) {
+			if normalized, err := models.NormalizeSpecQuantity(key, item); err == nil {
+				items = append(items, normalized)
+			}
+		}
+		if len(items) == 0 {
+			return strings.TrimSpace(value), nil
+		}
+		return strings.Join(items, ", "), nil
+	}
+
+	return value, nil
+}
+
+// NormalizeSpecs runs NormalizeSpecValue over a request spec map
+func NormalizeSpecs(specs map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string)
+	for key, value := range specs {
+		normalizedValue, err := NormalizeSpecValue(key, value)
+		if err != nil {
+			return nil, err
+		}
+		normalized[key] = normalizedValue
+	}
+	return normalized, nil
+}
+
 func (r *ComponentRepository) applyFiltersForPriceRange(query *gorm.DB, filters ComponentFilter) *gorm.DB {
 	if len(filters.CategoryIDs) > 0 {
 		query = query.Where("components.category_id IN ?", filters.CategoryIDs)
 	}
 
+	if len(filters.ExcludedIDs) > 0 {
+		query = query.Where("components.id <> ALL(CAST(? AS text[]))", idArray(filters.ExcludedIDs))
+	}
+
 	if len(filters.BrandIDs) > 0 {
 		query = query.Where(`
 			EXISTS (
@@ -494,7 +959,7 @@ func (r *ComponentRepository) applyFiltersForPriceRange(query *gorm.DB, filters
 			LOWER(components.models) LIKE ? OR
 			EXISTS (
 				SELECT 1 FROM component_brands cb
-				JOIN brands b ON cb.brand_id = b.id
+				JOIN brands b ON cb.brand_id = b.id AND b.deleted_at IS NULL
 				WHERE cb.component_id = components.id
 				AND LOWER(b.display_name) LIKE ?
 			)
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"encoding/json"
+	"pc-builder/backend/api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+func (r *ExchangeRateRepository) GetExchangeRates() ([]models.ExchangeRate, error) {
+	return loadExchangeRates(r.db)
+}
+
+func (r *ExchangeRateRepository) GetExchangeRate(currency string) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.db.Where("currency = ?", currency).First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// UpsertExchangeRate creates the rate or replaces the rate, symbol and decimals of the currency
+func (r *ExchangeRateRepository) UpsertExchangeRate(rate *models.ExchangeRate) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "symbol", "decimals", "updated_by", "updated_at"}),
+	}).Create(rate).Error
+}
+
+func (r *ExchangeRateRepository) DeleteExchangeRate(currency string) error {
+	return r.db.Delete(&models.ExchangeRate{}, "currency = ?", currency).Error
+}
+
+func loadExchangeRates(db *gorm.DB) ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	err := db.Order("currency").Find(&rates).Error
+	return rates, err
+}
+
+// priceInCurrencySQL returns the amount of components.price in the currency and its vars: the
+// stored entry when there is one, otherwise the base currency entry, or else the first stored
+// currency with a rate, converted and rounded like models.Price.WithDerived
+func priceInCurrencySQL(currency string) (string, []interface{}) {
+	return `COALESCE(
+			(SELECT (price_item->>'amount')::numeric
+			 FROM jsonb_array_elements(components.price) AS price_item
+			 WHERE price_item->>'currency' = ?
+			 LIMIT 1),
+			(SELECT ROUND((price_item->>'amount')::numeric * source_rate.rate::numeric / NULLIF(target_rate.rate, 0)::numeric, target_rate.decimals::int)
+			 FROM jsonb_array_elements(components.price) AS price_item
+			 JOIN exchange_rates AS source_rate ON source_rate.currency = price_item->>'currency'
+			 JOIN exchange_rates AS target_rate ON target_rate.currency = ?
+			 ORDER BY source_rate.currency = ? DESC, source_rate.currency
+			 LIMIT 1))`,
+		[]interface{}{currency, currency, models.BaseCurrency}
+}
+
+// attachDerivedPrices adds the converted prices of every rated currency a component lacks
+func (r *ComponentRepository) attachDerivedPrices(components []models.ComponentWithRelations) error {
+	if len(components) == 0 {
+		return nil
+	}
+
+	rates, err := loadExchangeRates(r.db)
+	if err != nil || len(rates) == 0 {
+		return err
+	}
+
+	for i := range components {
+		price, err := json.Marshal(models.ParsePrice(components[i].Price).WithDerived(rates))
+		if err != nil {
+			return err
+		}
+		components[i].Price = price
+	}
+
+	return nil
+}
+
+// deriveSlotPrices does the same for the preloaded components of builds, so build totals
+// cover every rated currency
+func deriveSlotPrices(db *gorm.DB, builds []models.Build) error {
+	rates, err := loadExchangeRates(db)
+	if err != nil || len(rates) == 0 {
+		return err
+	}
+
+	for i := range builds {
+		for j := range builds[i].Components {
+			component := builds[i].Components[j].Component
+			if component == nil {
+				continue
+			}
+
+			price, err := json.Marshal(models.ParsePrice(component.Price).WithDerived(rates))
+			if err != nil {
+				return err
+			}
+			component.Price = price
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"pc-builder/backend/api/models"
 	"strings"
@@ -8,7 +9,30 @@ import (
 	"gorm.io/gorm"
 )
 
-func (r *ComponentRepository) GetAvailableFilters(lang string) (*AvailableFilters, error) {
+// liveComponentSQL keeps the active, published components that are not in the trash, the
+// ones shown in the public catalog
+const liveComponentSQL = "components.is_active = true AND components.status = '" + models.ComponentStatusPublished +
+	"' AND components.deleted_at IS NULL"
+
+// liveCategorySQL keeps components whose category is not in the trash, for queries that do
+// not join categories
+const liveCategorySQL = "components.category_id IN (SELECT id FROM categories WHERE deleted_at IS NULL)"
+
+// idArray binds a list of IDs as a single Postgres text array, so an exclusion list of any
+// length is one query parameter instead of one per ID
+type idArray []string
+
+func (ids idArray) Value() (driver.Value, error) {
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		id = strings.ReplaceAll(id, `\`, `\\`)
+		quoted[i] = `"` + strings.ReplaceAll(id, `"`, `\"`) + `"`
+	}
+	return "{" + strings.Join(quoted, ",") + "}", nil
+}
+
+// GetAvailableFilters lists the filter options, the price range is given in the currency
+func (r *ComponentRepository) GetAvailableFilters(currency string) (*AvailableFilters, error) {
 	var categories []models.Category
 	var brands []models.Brand
 	var specs []models.ComponentSpec
@@ -16,8 +40,8 @@ func (r *ComponentRepository) GetAvailableFilters(lang string) (*AvailableFilter
 	err := r.db.
 		Select("categories.*, COUNT(components.id) as component_count").
 		Table("categories").
-		Joins("LEFT JOIN components ON categories.id = components.category_id AND components.is_active = true").
-		Where("categories.is_active = true").
+		Joins("LEFT JOIN components ON categories.id = components.category_id AND " + liveComponentSQL).
+		Where("categories.is_active = true AND categories.deleted_at IS NULL").
 		Group("categories.id").
 		Having("COUNT(components.id) > 0").
 		Order("categories.sort_order").
@@ -30,8 +54,8 @@ func (r *ComponentRepository) GetAvailableFilters(lang string) (*AvailableFilter
 		Select("brands.*, COUNT(DISTINCT components.id) as component_count").
 		Table("brands").
 		Joins("LEFT JOIN component_brands ON brands.id = component_brands.brand_id").
-		Joins("LEFT JOIN components ON components.id = component_brands.component_id AND components.is_active = true").
-		Where("brands.is_active = true").
+		Joins("LEFT JOIN components ON components.id = component_brands.component_id AND " + liveComponentSQL + " AND " + liveCategorySQL).
+		Where("brands.is_active = true AND brands.deleted_at IS NULL").
 		Group("brands.id").
 		Having("COUNT(DISTINCT components.id) > 0").
 		Order("brands.display_name").
@@ -44,7 +68,7 @@ func (r *ComponentRepository) GetAvailableFilters(lang string) (*AvailableFilter
 		Select("spec_key, spec_value, COUNT(*) as spec_count").
 		Table("component_specs").
 		Joins("JOIN components ON component_specs.component_id = components.id").
-		Where("component_specs.is_filterable = true AND components.is_active = true").
+		Where("component_specs.is_filterable = true AND " + liveComponentSQL + " AND " + liveCategorySQL).
 		Group("spec_key, spec_value").
 		Having("COUNT(*) > 0").
 		Order("spec_key, spec_count DESC").
@@ -54,17 +78,16 @@ func (r *ComponentRepository) GetAvailableFilters(lang string) (*AvailableFilter
 	}
 
 	var priceRange ComponentPriceRange
-	currency := getCurrencyForLang(lang)
 
+	priceSQL, vars := priceInCurrencySQL(currency)
 	err = r.db.
-		Raw(`
+		Raw(fmt.Sprintf(`
 			SELECT
-				MIN((price_item->>'amount')::numeric) as min_price,
-				MAX((price_item->>'amount')::numeric) as max_price
+				MIN(%[1]s) as min_price,
+				MAX(%[1]s) as max_price
 			FROM components
-			CROSS JOIN LATERAL jsonb_array_elements(price) as price_item
-			WHERE price_item->>'currency' = ? AND is_active = true
-		`, currency).
+			WHERE %[2]s AND %[3]s
+		`, priceSQL, liveComponentSQL, liveCategorySQL), append(vars, vars...)...).
 		Scan(&priceRange).Error
 	if err != nil {
 		return nil, err
@@ -84,6 +107,10 @@ func (r *ComponentRepository) applyFilters(query *gorm.DB, filters ComponentFilt
 		query = query.Where("components.category_id IN ?", filters.CategoryIDs)
 	}
 
+	if len(filters.ExcludedIDs) > 0 {
+		query = query.Where("components.id <> ALL(CAST(? AS text[]))", idArray(filters.ExcludedIDs))
+	}
+
 	if len(filters.BrandIDs) > 0 {
 		query = query.Where(`
 			EXISTS (
@@ -102,29 +129,22 @@ func (r *ComponentRepository) applyFilters(query *gorm.DB, filters ComponentFilt
 			LOWER(components.models) LIKE ? OR
 			EXISTS (
 				SELECT 1 FROM component_brands
-				JOIN brands ON component_brands.brand_id = brands.id
+				JOIN brands ON component_brands.brand_id = brands.id AND brands.deleted_at IS NULL
 				WHERE component_brands.component_id = components.id
 				AND LOWER(brands.display_name) LIKE ?
 			)
 		`, searchTerm, searchTerm, searchTerm, searchTerm)
 	}
 
-	// Price filters
-	currency := filters.Currency
-	if currency == "" {
-		currency = "VND"
-	}
+	// Price filters, on the stored or derived price in the filter currency
+	priceSQL, vars := priceInCurrencySQL(filterCurrency(filters))
 
 	if filters.MinPrice > 0 {
-		query = query.Where(
-			fmt.Sprintf(`price @? 'lax $[*] ? (@.currency == "%s" && @.amount >= %v)'`, currency, filters.MinPrice),
-		)
+		query = query.Where(priceSQL+" >= ?", append(vars, filters.MinPrice)...)
 	}
 
 	if filters.MaxPrice > 0 {
-		query = query.Where(
-			fmt.Sprintf(`price @? 'lax $[*] ? (@.currency == "%s" && @.amount <= %v)'`, currency, filters.MaxPrice),
-		)
+		query = query.Where(priceSQL+" <= ?", append(vars, filters.MaxPrice)...)
 	}
 
 	// Spec filters
@@ -173,6 +193,10 @@ func (r *ComponentRepository) applyFiltersForSummary(query *gorm.DB, filters Com
 		query = query.Where("components.category_id IN ?", filters.CategoryIDs)
 	}
 
+	if len(filters.ExcludedIDs) > 0 {
+		query = query.Where("components.id <> ALL(CAST(? AS text[]))", idArray(filters.ExcludedIDs))
+	}
+
 	if len(filters.BrandIDs) > 0 {
 		query = query.Where(`
 			EXISTS (
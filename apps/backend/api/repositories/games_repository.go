@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"pc-builder/backend/api/models"
+
+	"gorm.io/gorm"
+)
+
+type GameRepository struct {
+	db *gorm.DB
+}
+
+func NewGameRepository(db *gorm.DB) *GameRepository {
+	return &GameRepository{db: db}
+}
+
+// GetActiveGames returns the active games, limited to ids when given
+func (r *GameRepository) GetActiveGames(ids []string) ([]models.Game, error) {
+	query := r.db.Where("is_active = true")
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	var games []models.Game
+	err := query.Order("name").Find(&games).Error
+	return games, err
+}
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"encoding/json"
+	"math"
+	"pc-builder/backend/api/models"
+	"sort"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OfferRepository struct {
+	db *gorm.DB
+}
+
+func NewOfferRepository(db *gorm.DB) *OfferRepository {
+	return &OfferRepository{db: db}
+}
+
+func (r *OfferRepository) GetOffersByVendor(vendorID uuid.UUID, componentID string) ([]models.Offer, error) {
+	query := r.db.Where("vendor_id = ?", vendorID)
+	if componentID != "" {
+		query = query.Where("component_id = ?", componentID)
+	}
+
+	offers := []models.Offer{}
+	err := query.Order("updated_at DESC").Find(&offers).Error
+	return offers, err
+}
+
+func (r *OfferRepository) GetOfferByID(id uuid.UUID) (*models.Offer, error) {
+	var offer models.Offer
+	err := r.db.Where("id = ?", id).First(&offer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+func (r *OfferRepository) CreateOffer(offer *models.Offer) error {
+	return r.db.Create(offer).Error
+}
+
+func (r *OfferRepository) UpdateOffer(offer *models.Offer, updates map[string]interface{}) error {
+	return r.db.Model(offer).Updates(updates).Error
+}
+
+func (r *OfferRepository) DeleteOffer(id uuid.UUID) error {
+	return r.db.Delete(&models.Offer{}, "id = ?", id).Error
+}
+
+// attachOffers loads the active offers of every component in one query, adds their derived
+// prices and picks the best in-stock price per currency
+func (r *ComponentRepository) attachOffers(components []models.ComponentWithRelations) error {
+	if len(components) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(components))
+	for i, component := range components {
+		ids[i] = component.ID
+	}
+
+	var offers []models.Offer
+	err := r.db.Where("component_id IN ? AND is_active = true", ids).Order("updated_at DESC").Find(&offers).Error
+	if err != nil {
+		return err
+	}
+
+	rates, err := loadExchangeRates(r.db)
+	if err != nil {
+		return err
+	}
+
+	byComponent := make(map[string][]models.Offer)
+	for _, offer := range offers {
+		price := models.ParsePrice(offer.Price).WithDerived(rates)
+		offer.Price, err = json.Marshal(price)
+		if err != nil {
+			return err
+		}
+		byComponent[offer.ComponentID] = append(byComponent[offer.ComponentID], offer)
+	}
+
+	for i := range components {
+		componentOffers := byComponent[components[i].ID]
+		if componentOffers == nil {
+			componentOffers = []models.Offer{}
+		}
+
+		sort.SliceStable(componentOffers, func(a, b int) bool {
+			return baseAmount(componentOffers[a]) < baseAmount(componentOffers[b])
+		})
+
+		components[i].Offers = componentOffers
+		components[i].BestPrice = bestOfferPrices(componentOffers)
+	}
+
+	return nil
+}
+
+// bestOfferPrices keeps the cheapest in-stock offer per currency, in order of first appearance
+func bestOfferPrices(offers []models.Offer) []models.BestOfferPrice {
+	best := []models.BestOfferPrice{}
+	indexByCurrency := make(map[string]int)
+
+	for _, offer := range offers {
+		if offer.StockQuantity <= 0 {
+			continue
+		}
+
+		for _, item := range models.ParsePrice(offer.Price) {
+			candidate := models.BestOfferPrice{PriceItem: item, OfferID: offer.ID, VendorID: offer.VendorID}
+
+			i, exists := indexByCurrency[item.Currency]
+			if !exists {
+				indexByCurrency[item.Currency] = len(best)
+				best = append(best, candidate)
+			} else if item.Amount < best[i].Amount {
+				best[i] = candidate
+			}
+		}
+	}
+
+	return best
+}
+
+// baseAmount is the offer price in the base currency, offers without one sort last
+func baseAmount(offer models.Offer) float64 {
+	if item, exists := models.ParsePrice(offer.Price).Find(models.BaseCurrency); exists {
+		return item.Amount
+	}
+	return math.Inf(1)
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"pc-builder/backend/api/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PriceAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceAlertRepository(db *gorm.DB) *PriceAlertRepository {
+	return &PriceAlertRepository{db: db}
+}
+
+type PriceAlertListResponse struct {
+	Alerts     []models.PriceAlert `json:"alerts"`
+	Pagination PaginationMeta      `json:"pagination"`
+}
+
+func (r *PriceAlertRepository) CreateSubscription(subscription *models.PriceAlertSubscription) error {
+	return r.db.Create(subscription).Error
+}
+
+func (r *PriceAlertRepository) GetSubscriptionsByUser(userID uuid.UUID) ([]models.PriceAlertSubscription, error) {
+	var subscriptions []models.PriceAlertSubscription
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *PriceAlertRepository) GetSubscriptionByID(id uuid.UUID) (*models.PriceAlertSubscription, error) {
+	var subscription models.PriceAlertSubscription
+	err := r.db.Where("id = ?", id).First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *PriceAlertRepository) UpdateSubscription(subscription *models.PriceAlertSubscription, updates map[string]interface{}) error {
+	return r.db.Model(subscription).Updates(updates).Error
+}
+
+func (r *PriceAlertRepository) DeleteSubscription(id uuid.UUID) error {
+	return r.db.Delete(&models.PriceAlertSubscription{}, "id = ?", id).Error
+}
+
+// GetComponentSubscriptions returns the active subscriptions watching the component in the
+// currency
+func (r *PriceAlertRepository) GetComponentSubscriptions(componentID, currency string) ([]models.PriceAlertSubscription, error) {
+	var subscriptions []models.PriceAlertSubscription
+	err := r.db.
+		Where("component_id = ? AND currency = ? AND is_active = true", componentID, currency).
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// GetBuildSubscriptions returns the active subscriptions watching a build that contains the
+// component
+func (r *PriceAlertRepository) GetBuildSubscriptions(componentID, currency string) ([]models.PriceAlertSubscription, error) {
+	var subscriptions []models.PriceAlertSubscription
+	err := r.db.
+		Where("currency = ? AND is_active = true", currency).
+		Where("build_id IN (?)", r.db.Model(&models.BuildComponent{}).Select("build_id").Where("component_id = ?", componentID)).
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// GetActiveSubscriptions returns every active subscription
+func (r *PriceAlertRepository) GetActiveSubscriptions() ([]models.PriceAlertSubscription, error) {
+	var subscriptions []models.PriceAlertSubscription
+	err := r.db.Where("is_active = true").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// GetSubscribedComponents loads the name and stored price of the watched components
+func (r *PriceAlertRepository) GetSubscribedComponents(ids []string) ([]models.Component, error) {
+	var components []models.Component
+	if len(ids) == 0 {
+		return components, nil
+	}
+
+	err := r.db.Select("id, name, price").Where("id IN ?", ids).Find(&components).Error
+	return components, err
+}
+
+// CreateAlert stores the alert and stamps the subscription as triggered
+func (r *PriceAlertRepository) CreateAlert(alert *models.PriceAlert) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(alert).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.PriceAlertSubscription{}).
+			Where("id = ?", alert.SubscriptionID).
+			UpdateColumn("last_triggered_at", alert.CreatedAt).Error
+	})
+}
+
+// GetPendingAlerts returns the oldest alerts waiting for delivery, with the subscriber loaded
+func (r *PriceAlertRepository) GetPendingAlerts(limit int) ([]models.PriceAlert, error) {
+	var alerts []models.PriceAlert
+	err := r.db.Preload("Subscription.User").
+		Where("status = ?", models.AlertStatusPending).
+		Order("id").
+		Limit(limit).
+		Find(&alerts).Error
+	return alerts, err
+}
+
+// MarkAlertDelivered records the delivery outcome, an empty deliveryError means it was sent
+func (r *PriceAlertRepository) MarkAlertDelivered(alert *models.PriceAlert, deliveryError string) error {
+	updates := map[string]interface{}{
+		"status": models.AlertStatusSent,
+		"error":  "",
+	}
+	if deliveryError != "" {
+		updates["status"] = models.AlertStatusFailed
+		updates["error"] = deliveryError
+	} else {
+		updates["delivered_at"] = time.Now()
+	}
+
+	return r.db.Model(alert).Updates(updates).Error
+}
+
+func (r *PriceAlertRepository) GetAlertsByUser(userID uuid.UUID, pagination PaginationParams) (*PriceAlertListResponse, error) {
+	var totalRecords int64
+	err := r.db.Model(&models.PriceAlert{}).Where("user_id = ?", userID).Count(&totalRecords).Error
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := []models.PriceAlert{}
+	err = r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Offset((pagination.Page - 1) * pagination.PageSize).
+		Limit(pagination.PageSize).
+		Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int((totalRecords + int64(pagination.PageSize) - 1) / int64(pagination.PageSize))
+
+	return &PriceAlertListResponse{
+		Alerts: alerts,
+		Pagination: PaginationMeta{
+			CurrentPage:  pagination.Page,
+			PageSize:     pagination.PageSize,
+			TotalPages:   totalPages,
+			TotalRecords: totalRecords,
+		},
+	}, nil
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"pc-builder/backend/api/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PriceHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceHistoryRepository(db *gorm.DB) *PriceHistoryRepository {
+	return &PriceHistoryRepository{db: db}
+}
+
+// PriceHistoryFilter limits the time series, zero values are unbounded
+type PriceHistoryFilter struct {
+	Currency string
+	From     time.Time
+	To       time.Time // Exclusive
+}
+
+// CurrencyPriceHistory is the time series of one currency. Lowest and highest cover the
+// whole history, not only the requested range. Current is nil once the currency was
+// removed from the price.
+type CurrencyPriceHistory struct {
+	Currency    string              `json:"currency"`
+	Current     *models.PricePoint  `json:"current"`
+	LowestEver  *models.PricePoint  `json:"lowest_ever"`
+	HighestEver *models.PricePoint  `json:"highest_ever"`
+	Points      []models.PricePoint `json:"points"`
+}
+
+// GetPriceHistory returns the series of every currency of the component, oldest point first
+func (r *PriceHistoryRepository) GetPriceHistory(componentID string, filter PriceHistoryFilter) ([]CurrencyPriceHistory, error) {
+	var component models.Component
+	if err := r.db.Select("price").Where("id = ?", componentID).First(&component).Error; err != nil {
+		return nil, err
+	}
+	price := models.ParsePrice(component.Price).Stored()
+
+	query := r.db.Where("component_id = ?", componentID)
+	if filter.Currency != "" {
+		query = query.Where("currency = ?", filter.Currency)
+	}
+
+	var entries []models.PriceHistory
+	err := query.Order("currency, changed_at, id").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	histories := []CurrencyPriceHistory{}
+	for i, entry := range entries {
+		if i == 0 || entries[i-1].Currency != entry.Currency {
+			histories = append(histories, CurrencyPriceHistory{Currency: entry.Currency, Points: []models.PricePoint{}})
+		}
+		history := &histories[len(histories)-1]
+
+		point := models.PricePoint{Amount: entry.Amount, ChangedAt: entry.ChangedAt}
+		if _, exists := price.Find(entry.Currency); exists {
+			history.Current = &point
+		}
+
+		// The earliest occurrence wins ties so the annotation points at when the price was first reached
+		if history.LowestEver == nil || point.Amount < history.LowestEver.Amount {
+			history.LowestEver = &point
+		}
+		if history.HighestEver == nil || point.Amount > history.HighestEver.Amount {
+			history.HighestEver = &point
+		}
+
+		if !filter.From.IsZero() && entry.ChangedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !entry.ChangedAt.Before(filter.To) {
+			continue
+		}
+		history.Points = append(history.Points, point)
+	}
+
+	return histories, nil
+}
+
+// RecordPriceChanges writes a history row for every currency of current that is new or whose
+// amount differs from previous. It takes the caller's transaction so the row is only kept
+// when the price update itself is committed.
+func RecordPriceChanges(tx *gorm.DB, componentID string, previous, current models.Price, changedBy *uuid.UUID) error {
+	now := time.Now()
+
+	var entries []models.PriceHistory
+	for _, item := range current {
+		if old, exists := previous.Find(item.Currency); exists && old.Amount == item.Amount {
+			continue
+		}
+		entries = append(entries, models.PriceHistory{
+			ComponentID: componentID,
+			Currency:    item.Currency,
+			Amount:      item.Amount,
+			ChangedBy:   changedBy,
+			ChangedAt:   now,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+	return tx.Create(&entries).Error
+}
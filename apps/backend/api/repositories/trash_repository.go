@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"pc-builder/backend/api/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Trash types, the kinds of catalog rows that can be soft deleted
+const (
+	TrashComponents = "components"
+	TrashCategories = "categories"
+	TrashBrands     = "brands"
+)
+
+// ErrUsedByBuilds is returned when purging a component that saved builds still contain,
+// since deleting it would silently drop it from them
+var ErrUsedByBuilds = errors.New("component is used by saved builds")
+
+// ErrCategoryInUse is returned when purging a category that components, trashed or not,
+// still belong to
+var ErrCategoryInUse = errors.New("category still has components")
+
+// ErrBrandInUse is returned when purging a brand that components, trashed or not, are still
+// linked to, since the brand links would otherwise be deleted with it
+var ErrBrandInUse = errors.New("brand is still assigned to components")
+
+// TrashResponse is one page of trashed rows of a single type
+type TrashResponse struct {
+	Type       string         `json:"type"`
+	Items      interface{}    `json:"items"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+type TrashRepository struct {
+	db *gorm.DB
+}
+
+func NewTrashRepository(db *gorm.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// trashModel maps a trash type to its model and an empty slice to list it into
+func trashModel(kind string) (interface{}, interface{}, error) {
+	switch kind {
+	case TrashComponents:
+		return &models.Component{}, &[]models.Component{}, nil
+	case TrashCategories:
+		return &models.Category{}, &[]models.Category{}, nil
+	case TrashBrands:
+		return &models.Brand{}, &[]models.Brand{}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown trash type %q", kind)
+	}
+}
+
+// IsTrashType reports whether the kind names a type that can be trashed
+func IsTrashType(kind string) bool {
+	_, _, err := trashModel(kind)
+	return err == nil
+}
+
+// MoveToTrash soft deletes the row and records who did it. Rows already in the trash are
+// left untouched and reported as not found.
+func (r *TrashRepository) MoveToTrash(kind, id string, deletedBy *uuid.UUID) error {
+	model, _, err := trashModel(kind)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.Model(model).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_at": time.Now(),
+		"deleted_by": deletedBy,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetTrash lists the trashed rows of the type, most recently deleted first
+func (r *TrashRepository) GetTrash(kind string, pagination PaginationParams) (*TrashResponse, error) {
+	model, items, err := trashModel(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalRecords int64
+	err = r.db.Unscoped().Model(model).Where("deleted_at IS NOT NULL").Count(&totalRecords).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Unscoped().Model(model).
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Offset((pagination.Page - 1) * pagination.PageSize).
+		Limit(pagination.PageSize).
+		Find(items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int((totalRecords + int64(pagination.PageSize) - 1) / int64(pagination.PageSize))
+
+	return &TrashResponse{
+		Type:  kind,
+		Items: items,
+		Pagination: PaginationMeta{
+			CurrentPage:  pagination.Page,
+			PageSize:     pagination.PageSize,
+			TotalPages:   totalPages,
+			TotalRecords: totalRecords,
+		},
+	}, nil
+}
+
+// Restore takes a trashed row out of the trash
+func (r *TrashRepository) Restore(kind, id string) error {
+	model, _, err := trashModel(kind)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.Unscoped().Model(model).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"deleted_by": nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge permanently deletes a trashed row. Specs, brand links, offers and the other rows
+// hanging off a component go with it through their cascading foreign keys, a category
+// still holding components is refused by the database.
+func (r *TrashRepository) Purge(kind, id string) error {
+	model, _, err := trashModel(kind)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var references int64
+		switch kind {
+		case TrashComponents:
+			err = tx.Model(&models.BuildComponent{}).Where("component_id = ?", id).Count(&references).Error
+			err = inUse(err, references, ErrUsedByBuilds)
+		case TrashCategories:
+			err = tx.Unscoped().Model(&models.Component{}).Where("category_id = ?", id).Count(&references).Error
+			err = inUse(err, references, ErrCategoryInUse)
+		case TrashBrands:
+			err = tx.Model(&models.ComponentBrands{}).Where("brand_id = ?", id).Count(&references).Error
+			err = inUse(err, references, ErrBrandInUse)
+		}
+		if err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(model)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// inUse turns a reference count into the sentinel error when rows still point at the purged one
+func inUse(err error, references int64, sentinel error) error {
+	if err != nil {
+		return err
+	}
+	if references > 0 {
+		return fmt.Errorf("%w: %d", sentinel, references)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	controller "pc-builder/backend/api/controllers"
 	"pc-builder/backend/api/middlewares"
+	"pc-builder/backend/api/models"
 	"pc-builder/backend/db"
 	"pc-builder/backend/services"
 	"time"
@@ -11,14 +12,21 @@ import (
 )
 
 const (
-	RoleUser   = "user"
-	RoleAdmin  = "admin"
-	RoleVendor = "vendor"
+	RoleUser   = models.RoleUser
+	RoleAdmin  = models.RoleAdmin
+	RoleVendor = models.RoleVendor
 )
 
-func RegisterRoutes(router *gin.Engine, cloudinaryService *services.CloudinaryService) {
-	componentController := controller.NewComponentController(db.DB)
+func RegisterRoutes(router *gin.Engine, cloudinaryService *services.CloudinaryService, priceAlertService *services.PriceAlertService) {
+	componentController := controller.NewComponentController(db.DB, priceAlertService)
 	imageController := controller.NewImageController(cloudinaryService)
+	buildController := controller.NewBuildController(db.DB)
+	compatibilityController := controller.NewCompatibilityController(db.DB)
+	benchmarkController := controller.NewBenchmarkController(db.DB)
+	priceAlertController := controller.NewPriceAlertController(db.DB, priceAlertService)
+	exchangeRateController := controller.NewExchangeRateController(db.DB, priceAlertService)
+	offerController := controller.NewOfferController(db.DB)
+	trashController := controller.NewTrashController(db.DB)
 
 	// Health check route
 	router.GET("/health", func(c *gin.Context) {
@@ -74,8 +82,11 @@ func RegisterRoutes(router *gin.Engine, cloudinaryService *services.CloudinarySe
 	components.Use(middlewares.ValidateQueryParams())
 	{
 		components.GET("/all", componentController.GetAllComponents)
+		components.GET("/compare", componentController.CompareComponents)
 		components.GET("/:id", componentController.GetComponentByID)
-		components.GET("", componentController.GetComponentsWithPagination)
+		components.GET("/:id/price-history", componentController.GetPriceHistory)
+		// A token lets compatible_with use the caller's private builds
+		components.GET("", middlewares.OptionalJWTMiddleware(), componentController.GetComponentsWithPagination)
 		// Get available filters
 		components.GET("/filters", componentController.GetAvailableFilters)
 	}
@@ -90,6 +101,59 @@ func RegisterRoutes(router *gin.Engine, cloudinaryService *services.CloudinarySe
 		brands.GET("", componentController.GetAllBrands)
 	}
 
+	exchangeRates := api.Group("/exchange-rates")
+	{
+		exchangeRates.GET("", exchangeRateController.GetExchangeRates)
+	}
+
+	games := api.Group("/games")
+	{
+		games.GET("", benchmarkController.GetGames)
+	}
+
+	compatibility := api.Group("/compatibility")
+	{
+		compatibility.POST("/check", compatibilityController.CheckCompatibility)
+		compatibility.POST("/power", compatibilityController.EstimatePower)
+	}
+
+	// Saved builds of the authenticated user
+	builds := api.Group("/builds")
+	builds.Use(middlewares.JWTMiddleware())
+	{
+		builds.GET("", buildController.GetMyBuilds)
+		builds.POST("", buildController.CreateBuild)
+		builds.POST("/generate", buildController.GenerateBuilds)
+		builds.POST("/import", buildController.ImportBuild)
+		builds.GET("/:id", buildController.GetBuildByID)
+		builds.PUT("/:id", buildController.UpdateBuild)
+		builds.DELETE("/:id", buildController.DeleteBuild)
+		builds.GET("/:id/export", buildController.ExportBuild)
+		builds.GET("/:id/diff", buildController.DiffBuilds)
+		builds.POST("/:id/upgrades", buildController.SuggestUpgrades)
+		builds.GET("/:id/performance", buildController.GetBuildPerformance)
+		builds.POST("/:id/fork", buildController.ForkBuild)
+		builds.POST("/:id/share", buildController.ShareBuild)
+		builds.DELETE("/:id/share", buildController.RevokeShare)
+	}
+
+	// Price-drop alerts of the authenticated user
+	alerts := api.Group("/alerts")
+	alerts.Use(middlewares.JWTMiddleware())
+	{
+		alerts.GET("", priceAlertController.GetMyAlerts)
+		alerts.GET("/subscriptions", priceAlertController.GetMySubscriptions)
+		alerts.POST("/subscriptions", priceAlertController.CreateSubscription)
+		alerts.PATCH("/subscriptions/:id", priceAlertController.UpdateSubscription)
+		alerts.DELETE("/subscriptions/:id", priceAlertController.DeleteSubscription)
+	}
+
+	// Public read-only view of shared builds
+	shared := api.Group("/shared")
+	{
+		shared.GET("/builds/:slug", buildController.GetSharedBuild)
+	}
+
 	// Protected admin routes
 	admin := api.Group("/admin")
 	admin.Use(middlewares.JWTMiddleware(), middlewares.RequireRole(RoleAdmin))
@@ -97,6 +161,8 @@ func RegisterRoutes(router *gin.Engine, cloudinaryService *services.CloudinarySe
 		admin.GET("/users", controller.GetAllUsers)
 
 		// Admin component management
+		admin.GET("/components/:id/revisions", componentController.GetComponentRevisions)
+		admin.GET("/components/:id/revisions/diff", componentController.DiffComponentRevisions)
 		adminComponents := admin.Group("/components")
 		adminComponents.Use(middlewares.ValidateComponentInput())
 		{
@@ -104,6 +170,16 @@ func RegisterRoutes(router *gin.Engine, cloudinaryService *services.CloudinarySe
 			adminComponents.POST("/bulk", componentController.BulkCreateComponents)
 			adminComponents.PUT("/:id", componentController.UpdateComponent)
 			adminComponents.DELETE("/:id", componentController.DeleteComponent)
+			adminComponents.POST("/:id/revisions/:revision/rollback", componentController.RollbackComponent)
+		}
+
+		// Admin review of vendor components and changes
+		admin.GET("/reviews", componentController.GetReviewQueue)
+		adminReviews := admin.Group("/reviews")
+		adminReviews.Use(middlewares.ValidateComponentInput())
+		{
+			adminReviews.POST("/:id/approve", componentController.ApproveComponent)
+			adminReviews.POST("/:id/reject", componentController.RejectComponent)
 		}
 
 		// Admin category management
@@ -112,6 +188,7 @@ func RegisterRoutes(router *gin.Engine, cloudinaryService *services.CloudinarySe
 		{
 			adminCategories.POST("", componentController.CreateCategory)
 			adminCategories.PATCH("/:id", componentController.UpdateCategory)
+			adminCategories.DELETE("/:id", componentController.DeleteCategory)
 		}
 
 		// Admin brand management
@@ -120,6 +197,46 @@ func RegisterRoutes(router *gin.Engine, cloudinaryService *services.CloudinarySe
 		{
 			adminBrands.POST("", componentController.CreateBrand)
 			adminBrands.PATCH("/:id", componentController.UpdateBrand)
+			adminBrands.DELETE("/:id", componentController.DeleteBrand)
+		}
+
+		// Admin compatibility rule management
+		admin.GET("/compatibility-rules", compatibilityController.GetCompatibilityRules)
+		adminCompatibilityRules := admin.Group("/compatibility-rules")
+		adminCompatibilityRules.Use(middlewares.ValidateComponentInput())
+		{
+			adminCompatibilityRules.POST("", compatibilityController.CreateCompatibilityRule)
+			adminCompatibilityRules.PATCH("/:id", compatibilityController.UpdateCompatibilityRule)
+			adminCompatibilityRules.DELETE("/:id", compatibilityController.DeleteCompatibilityRule)
+		}
+
+		// Admin benchmark management
+		admin.GET("/benchmarks", benchmarkController.GetBenchmarks)
+		adminBenchmarks := admin.Group("/benchmarks")
+		adminBenchmarks.Use(middlewares.ValidateComponentInput())
+		{
+			adminBenchmarks.POST("", benchmarkController.CreateBenchmark)
+			adminBenchmarks.POST("/bulk", benchmarkController.BulkImportBenchmarks)
+			adminBenchmarks.PATCH("/:id", benchmarkController.UpdateBenchmark)
+			adminBenchmarks.DELETE("/:id", benchmarkController.DeleteBenchmark)
+		}
+
+		// Admin exchange rate management
+		admin.GET("/exchange-rates", exchangeRateController.GetExchangeRates)
+		adminExchangeRates := admin.Group("/exchange-rates")
+		adminExchangeRates.Use(middlewares.ValidateComponentInput())
+		{
+			adminExchangeRates.PUT("/:currency", exchangeRateController.PutExchangeRate)
+			adminExchangeRates.DELETE("/:currency", exchangeRateController.DeleteExchangeRate)
+		}
+
+		// Admin trash of soft deleted components, categories and brands
+		admin.GET("/trash", trashController.GetTrash)
+		adminTrash := admin.Group("/trash")
+		adminTrash.Use(middlewares.ValidateComponentInput())
+		{
+			adminTrash.POST("/:type/:id/restore", trashController.RestoreFromTrash)
+			adminTrash.DELETE("/:type/:id", trashController.PurgeFromTrash)
 		}
 
 		adminImages := admin.Group("/images")
@@ -133,14 +250,26 @@ func RegisterRoutes(router *gin.Engine, cloudinaryService *services.CloudinarySe
 	vendor := api.Group("/vendor")
 	vendor.Use(middlewares.JWTMiddleware(), middlewares.RequireRole(RoleVendor))
 	{
+		vendor.GET("/components", componentController.GetMyComponents)
 		vendorComponents := vendor.Group("/components")
 		vendorComponents.Use(middlewares.ValidateComponentInput())
 		{
 			vendorComponents.POST("", componentController.CreateComponent)
 			vendorComponents.POST("/bulk", componentController.BulkCreateComponents)
 			vendorComponents.PUT("/:id", componentController.UpdateComponent)
-			// Vendors can only soft-delete (set inactive), not hard delete
+			vendorComponents.POST("/:id/submit", componentController.SubmitComponent)
+			// Vendors can only move their components to the trash, purging is left to admins
 			vendorComponents.PUT("/:id/deactivate", componentController.DeleteComponent)
 		}
+
+		// Offers of the vendor on catalog components
+		vendor.GET("/offers", offerController.GetMyOffers)
+		vendorOffers := vendor.Group("/offers")
+		vendorOffers.Use(middlewares.ValidateComponentInput())
+		{
+			vendorOffers.POST("", offerController.CreateOffer)
+			vendorOffers.PATCH("/:id", offerController.UpdateOffer)
+			vendorOffers.DELETE("/:id", offerController.DeleteOffer)
+		}
 	}
 }
@@ -11,6 +11,7 @@ type Config struct {
 	Environment    string
 	DB             PostgresConfig
 	Cloudinary     CloudinaryConfig
+	PriceAlerts    PriceAlertConfig
 	Port           string
 	AllowedOrigins []string
 }
@@ -31,6 +32,12 @@ type CloudinaryConfig struct {
 	ApiSecret string
 }
 
+// PriceAlertConfig enables the webhook notifier when WebhookURL is set
+type PriceAlertConfig struct {
+	WebhookURL    string
+	WebhookSecret string
+}
+
 func LoadEnv() (*Config, error) {
 	godotenv.Load()
 
@@ -58,6 +65,10 @@ func LoadEnv() (*Config, error) {
 			ApiKey:    os.Getenv("CLOUDINARY_API_KEY"),
 			ApiSecret: os.Getenv("CLOUDINARY_API_SECRET"),
 		},
+		PriceAlerts: PriceAlertConfig{
+			WebhookURL:    os.Getenv("PRICE_ALERT_WEBHOOK_URL"),
+			WebhookSecret: os.Getenv("PRICE_ALERT_WEBHOOK_SECRET"),
+		},
 	}
 
 	return cfg, nil
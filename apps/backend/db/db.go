@@ -47,6 +47,18 @@ func InitPostgres(cfg *config.Config) *gorm.DB {
 		&models.ComponentBrands{},
 		&models.ComponentSpec{},
 		&models.User{},
+		&models.Build{},
+		&models.BuildComponent{},
+		&models.CompatibilityRule{},
+		&models.Benchmark{},
+		&models.Game{},
+		&models.PriceHistory{},
+		&models.PriceAlertSubscription{},
+		&models.PriceAlert{},
+		&models.EmailOutbox{},
+		&models.ExchangeRate{},
+		&models.Offer{},
+		&models.ComponentRevision{},
 	); err != nil {
 
 		log.Fatalf("❌ AutoMigrate failed: %v", err)
@@ -54,6 +66,8 @@ func InitPostgres(cfg *config.Config) *gorm.DB {
 
 	log.Println("✅ Connected to PostgreSQL")
 
+	seedDefaults(DB)
+
 	sqlDB, err := DB.DB()
 	if err != nil {
 		log.Fatalf("Failed to get underlying sql.DB: %v", err)
@@ -85,6 +99,7 @@ func createIndexes(db *gorm.DB) error {
 		"CREATE INDEX IF NOT EXISTS idx_components_is_active ON components(is_active)",
 		"CREATE INDEX IF NOT EXISTS idx_components_category_brand ON components(category_id, brand_id)",
 		"CREATE INDEX IF NOT EXISTS idx_components_active_category ON components(is_active, category_id) WHERE is_active = true",
+		"CREATE INDEX IF NOT EXISTS idx_components_review_queue ON components(submitted_at) WHERE status = 'pending_review' OR pending_changes IS NOT NULL",
 
 		// JSONB indexes for price filtering
 		"CREATE INDEX IF NOT EXISTS idx_components_price_gin ON components USING gin(price)",
@@ -107,6 +122,28 @@ func createIndexes(db *gorm.DB) error {
 		// User indexes
 		"CREATE INDEX IF NOT EXISTS idx_users_email ON users(email)",
 		"CREATE INDEX IF NOT EXISTS idx_users_role ON users(role)",
+
+		// Build indexes
+		"CREATE INDEX IF NOT EXISTS idx_builds_user_updated ON builds(user_id, updated_at DESC)",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_build_components_build_component ON build_components(build_id, component_id)",
+
+		// Compatibility rule indexes
+		"CREATE INDEX IF NOT EXISTS idx_compatibility_rules_active ON compatibility_rules(is_active) WHERE is_active = true",
+
+		// Benchmark indexes, the unique one backs the bulk import upsert
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_benchmarks_component_name_source ON benchmarks(component_id, name, source)",
+		"CREATE INDEX IF NOT EXISTS idx_benchmarks_name_measured ON benchmarks(name, measured_at DESC)",
+
+		// Price history index, covers the per component and currency time series
+		"CREATE INDEX IF NOT EXISTS idx_price_history_component_currency_changed ON price_history(component_id, currency, changed_at)",
+
+		// Offer indexes, a vendor has one offer per component and condition
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_offers_component_vendor_condition ON offers(component_id, vendor_id, condition)",
+		"CREATE INDEX IF NOT EXISTS idx_offers_component_active ON offers(component_id) WHERE is_active = true",
+
+		// Price alert indexes
+		"CREATE INDEX IF NOT EXISTS idx_price_alert_subscriptions_active ON price_alert_subscriptions(currency) WHERE is_active = true",
+		"CREATE INDEX IF NOT EXISTS idx_price_alerts_user_created ON price_alerts(user_id, created_at DESC)",
 	}
 
 	for _, indexSQL := range indexes {
@@ -0,0 +1,169 @@
+package db
+
+import (
+	"log"
+	"pc-builder/backend/api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultCompatibilityRules are inserted once so a fresh database can check builds.
+// Admins can edit or disable them afterwards, existing rows are never overwritten.
+var defaultCompatibilityRules = []models.CompatibilityRule{
+	{
+		ID:               "cpu_mainboard_socket",
+		Name:             "CPU socket",
+		SourceCategoryID: models.CategoryCPU,
+		SourceSpecKey:    "socket",
+		Operator:         models.RuleOperatorEquals,
+		TargetCategoryID: models.CategoryMainboard,
+		TargetSpecKey:    "socket",
+		Severity:         models.RuleSeverityError,
+		Message:          "CPU socket does not match the mainboard socket",
+	},
+	{
+		ID:               "mainboard_ram_memory_type",
+		Name:             "Memory type",
+		SourceCategoryID: models.CategoryMainboard,
+		SourceSpecKey:    "memory_type",
+		Operator:         models.RuleOperatorContains,
+		TargetCategoryID: models.CategoryRAM,
+		TargetSpecKey:    "memory_type",
+		Severity:         models.RuleSeverityError,
+		Message:          "RAM memory type is not supported by the mainboard",
+	},
+	{
+		ID:               "cpu_ram_memory_type",
+		Name:             "CPU memory controller",
+		SourceCategoryID: models.CategoryCPU,
+		SourceSpecKey:    "memory_type",
+		Operator:         models.RuleOperatorContains,
+		TargetCategoryID: models.CategoryRAM,
+		TargetSpecKey:    "memory_type",
+		Severity:         models.RuleSeverityWarning,
+		Message:          "RAM memory type is not supported by the CPU",
+	},
+	{
+		ID:               "case_mainboard_form_factor",
+		Name:             "Mainboard form factor",
+		SourceCategoryID: models.CategoryCase,
+		SourceSpecKey:    "form_factor",
+		Operator:         models.RuleOperatorContains,
+		TargetCategoryID: models.CategoryMainboard,
+		TargetSpecKey:    "form_factor",
+		Severity:         models.RuleSeverityError,
+		Message:          "Mainboard form factor does not fit in the case",
+	},
+	{
+		ID:               "cooler_cpu_socket",
+		Name:             "Cooler socket",
+		SourceCategoryID: models.CategoryCooler,
+		SourceSpecKey:    "socket",
+		Operator:         models.RuleOperatorContains,
+		TargetCategoryID: models.CategoryCPU,
+		TargetSpecKey:    "socket",
+		Severity:         models.RuleSeverityError,
+		Message:          "CPU cooler does not support the CPU socket",
+	},
+	{
+		ID:               "case_psu_form_factor",
+		Name:             "PSU form factor",
+		SourceCategoryID: models.CategoryCase,
+		SourceSpecKey:    "psu_form_factor",
+		Operator:         models.RuleOperatorContains,
+		TargetCategoryID: models.CategoryPSU,
+		TargetSpecKey:    "form_factor",
+		Severity:         models.RuleSeverityWarning,
+		Message:          "PSU form factor may not fit in the case",
+	},
+}
+
+// defaultGames are the reference figures FPS estimates are scaled from, relative to a
+// Cinebench R23 single-core score of 2000 and a 3DMark Time Spy score of 17800
+var defaultGames = []models.Game{
+	{ID: "cyberpunk_2077", Name: "Cyberpunk 2077", ReferenceCPUFPS: 140, ReferenceGPUFPS: 95},
+	{ID: "counter_strike_2", Name: "Counter-Strike 2", ReferenceCPUFPS: 420, ReferenceGPUFPS: 380},
+	{ID: "fortnite", Name: "Fortnite", ReferenceCPUFPS: 260, ReferenceGPUFPS: 180},
+	{ID: "red_dead_redemption_2", Name: "Red Dead Redemption 2", ReferenceCPUFPS: 150, ReferenceGPUFPS: 100},
+	{ID: "baldurs_gate_3", Name: "Baldur's Gate 3", ReferenceCPUFPS: 110, ReferenceGPUFPS: 120},
+}
+
+func seedDefaults(db *gorm.DB) {
+	for _, rule := range defaultCompatibilityRules {
+		rule.IsActive = true
+		err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rule).Error
+		if err != nil {
+			log.Printf("⚠️ Failed to seed compatibility rule %s: %v", rule.ID, err)
+		}
+	}
+
+	for _, game := range defaultGames {
+		game.CPUBenchmark = models.GamingCPUBenchmark
+		game.GPUBenchmark = models.DefaultBenchmarks[models.CategoryGPU]
+		game.ReferenceCPUScore = 2000
+		game.ReferenceGPUScore = 17800
+		game.IsActive = true
+		err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&game).Error
+		if err != nil {
+			log.Printf("⚠️ Failed to seed game %s: %v", game.ID, err)
+		}
+	}
+
+	// Rates are relative to the base currency, which must always have a row for conversions
+	baseRate := models.ExchangeRate{Currency: models.BaseCurrency, Rate: 1, Symbol: "₫", Decimals: 0}
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&baseRate).Error
+	if err != nil {
+		log.Printf("⚠️ Failed to seed base exchange rate: %v", err)
+	}
+
+	backfillPriceHistory(db)
+	backfillComponentRevisions(db)
+}
+
+// backfillPriceHistory records the current prices of components that have no history yet,
+// such as the ones created before price tracking, so every series starts with a point
+func backfillPriceHistory(db *gorm.DB) {
+	err := db.Exec(`
+		INSERT INTO price_history (component_id, currency, amount, changed_at)
+		SELECT c.id, item->>'currency', (item->>'amount')::numeric, c.updated_at
+		FROM components c, jsonb_array_elements(c.price) AS item
+		WHERE jsonb_typeof(c.price) = 'array'
+			AND item->>'currency' IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM price_history ph WHERE ph.component_id = c.id)
+	`).Error
+	if err != nil {
+		log.Printf("⚠️ Failed to backfill price history: %v", err)
+	}
+}
+
+// backfillComponentRevisions writes a first revision for components created before revisions
+// were recorded, so every component has a state to roll back to
+func backfillComponentRevisions(db *gorm.DB) {
+	err := db.Exec(`
+		INSERT INTO component_revisions (component_id, revision, action, snapshot, created_at)
+		SELECT c.id, 1, ?, jsonb_build_object(
+			'name', c.name,
+			'category_id', c.category_id,
+			'models', c.models,
+			'is_active', c.is_active,
+			'in_stock', c.in_stock,
+			'price', c.price,
+			'image_url', c.image_url,
+			'brands', COALESCE((
+				SELECT jsonb_agg(jsonb_build_object('brand_id', cb.brand_id, 'is_primary', cb.is_primary)
+					ORDER BY cb.is_primary DESC, cb.brand_id)
+				FROM component_brands cb WHERE cb.component_id = c.id
+			), '[]'::jsonb),
+			'specs', COALESCE((
+				SELECT jsonb_object_agg(cs.spec_key, cs.spec_value)
+				FROM component_specs cs WHERE cs.component_id = c.id
+			), '{}'::jsonb)
+		), c.updated_at
+		FROM components c
+		WHERE NOT EXISTS (SELECT 1 FROM component_revisions cr WHERE cr.component_id = c.id)
+	`, models.RevisionActionImport).Error
+	if err != nil {
+		log.Printf("⚠️ Failed to backfill component revisions: %v", err)
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 var appConfig *config.Config
 var cloudinaryService *services.CloudinaryService
+var priceAlertService *services.PriceAlertService
 
 func init() {
 	var err error
@@ -39,6 +40,16 @@ func init() {
 		log.Println("✅ Cloudinary service initialized")
 	}
 
+	notifiers := []services.Notifier{services.NewEmailOutboxNotifier(db.DB)}
+	if appConfig.PriceAlerts.WebhookURL != "" {
+		notifiers = append(notifiers, services.NewWebhookNotifier(
+			appConfig.PriceAlerts.WebhookURL,
+			appConfig.PriceAlerts.WebhookSecret,
+		))
+		log.Println("✅ Price alert webhook enabled")
+	}
+	priceAlertService = services.NewPriceAlertService(db.DB, notifiers...)
+	priceAlertService.StartDelivery(time.Minute)
 }
 
 func main() {
@@ -64,7 +75,7 @@ func main() {
 		context.JSON(http.StatusOK, gin.H{"message": "Welcome to PC Builder API"})
 	})
 
-	routes.RegisterRoutes(router, cloudinaryService)
+	routes.RegisterRoutes(router, cloudinaryService, priceAlertService)
 
 	port := appConfig.Port
 
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type DiffComponent struct {
+	ComponentID string `json:"component_id"`
+	Name        string `json:"name"`
+	CategoryID  string `json:"category_id"`
+	Quantity    int    `json:"quantity"`
+}
+
+// ComponentSwap is a part replaced by another of the same category
+type ComponentSwap struct {
+	CategoryID string        `json:"category_id"`
+	From       DiffComponent `json:"from"`
+	To         DiffComponent `json:"to"`
+}
+
+type QuantityChange struct {
+	DiffComponent
+	FromQuantity int `json:"from_quantity"`
+}
+
+type PriceDelta struct {
+	Currency string  `json:"currency"`
+	Symbol   string  `json:"symbol,omitempty"`
+	From     float64 `json:"from"`
+	To       float64 `json:"to"`
+	Delta    float64 `json:"delta"`
+}
+
+type PowerDelta struct {
+	FromLoad        float64 `json:"from_load"`
+	ToLoad          float64 `json:"to_load"`
+	LoadDelta       float64 `json:"load_delta"`
+	FromRecommended float64 `json:"from_recommended"`
+	ToRecommended   float64 `json:"to_recommended"`
+	FromVerdict     string  `json:"from_verdict"`
+	ToVerdict       string  `json:"to_verdict"`
+}
+
+type CompatibilitySummary struct {
+	Compatible   bool `json:"compatible"`
+	ErrorCount   int  `json:"error_count"`
+	WarningCount int  `json:"warning_count"`
+}
+
+type CompatibilityDelta struct {
+	From      CompatibilitySummary `json:"from"`
+	To        CompatibilitySummary `json:"to"`
+	NewIssues []RuleResult         `json:"new_issues"` // Failing in the target build only
+	Resolved  []RuleResult         `json:"resolved"`   // Failing in the source build only
+}
+
+// BuildDiff describes how to get from the source build to the target build
+type BuildDiff struct {
+	FromBuildID     string             `json:"from_build_id"`
+	ToBuildID       string             `json:"to_build_id"`
+	Added           []DiffComponent    `json:"added"`
+	Removed         []DiffComponent    `json:"removed"`
+	Swapped         []ComponentSwap    `json:"swapped"`
+	QuantityChanged []QuantityChange   `json:"quantity_changed"`
+	Unchanged       []DiffComponent    `json:"unchanged"`
+	Totals          []PriceDelta       `json:"totals"`
+	Power           PowerDelta         `json:"power"`
+	Compatibility   CompatibilityDelta `json:"compatibility"`
+}
+
+type BuildDiffService struct {
+	compatibility *CompatibilityService
+}
+
+func NewBuildDiffService(db *gorm.DB) *BuildDiffService {
+	return &BuildDiffService{
+		compatibility: NewCompatibilityService(db),
+	}
+}
+
+// Diff compares two builds slot by slot. A part removed and another added in the same
+// category are reported as a swap.
+func (s *BuildDiffService) Diff(from, to *models.BuildWithTotals) (*BuildDiff, error) {
+	diff := &BuildDiff{
+		FromBuildID:     from.ID.String(),
+		ToBuildID:       to.ID.String(),
+		Added:           []DiffComponent{},
+		Removed:         []DiffComponent{},
+		Swapped:         []ComponentSwap{},
+		QuantityChanged: []QuantityChange{},
+		Unchanged:       []DiffComponent{},
+	}
+
+	fromSlots := diffComponents(from.Build)
+	toSlots := diffComponents(to.Build)
+
+	toByID := make(map[string]DiffComponent)
+	for _, component := range toSlots {
+		toByID[component.ComponentID] = component
+	}
+	fromByID := make(map[string]DiffComponent)
+	for _, component := range fromSlots {
+		fromByID[component.ComponentID] = component
+	}
+
+	removedByCategory := make(map[string][]DiffComponent)
+	addedByCategory := make(map[string][]DiffComponent)
+	categories := []string{}
+	trackCategory := func(category string) {
+		if _, exists := removedByCategory[category]; !exists {
+			if _, exists := addedByCategory[category]; !exists {
+				categories = append(categories, category)
+			}
+		}
+	}
+
+	for _, component := range fromSlots {
+		target, exists := toByID[component.ComponentID]
+		switch {
+		case !exists:
+			trackCategory(component.CategoryID)
+			removedByCategory[component.CategoryID] = append(removedByCategory[component.CategoryID], component)
+		case target.Quantity != component.Quantity:
+			diff.QuantityChanged = append(diff.QuantityChanged, QuantityChange{DiffComponent: target, FromQuantity: component.Quantity})
+		default:
+			diff.Unchanged = append(diff.Unchanged, component)
+		}
+	}
+
+	for _, component := range toSlots {
+		if _, exists := fromByID[component.ComponentID]; !exists {
+			trackCategory(component.CategoryID)
+			addedByCategory[component.CategoryID] = append(addedByCategory[component.CategoryID], component)
+		}
+	}
+
+	for _, category := range categories {
+		removed, added := removedByCategory[category], addedByCategory[category]
+		for len(removed) > 0 && len(added) > 0 {
+			diff.Swapped = append(diff.Swapped, ComponentSwap{CategoryID: category, From: removed[0], To: added[0]})
+			removed, added = removed[1:], added[1:]
+		}
+		diff.Removed = append(diff.Removed, removed...)
+		diff.Added = append(diff.Added, added...)
+	}
+
+	diff.Totals = diffTotals(from.Totals, to.Totals)
+
+	fromReport, err := s.evaluateBuild(from.Build)
+	if err != nil {
+		return nil, err
+	}
+	toReport, err := s.evaluateBuild(to.Build)
+	if err != nil {
+		return nil, err
+	}
+
+	diff.Power = PowerDelta{
+		FromLoad:        fromReport.Power.EstimatedLoad,
+		ToLoad:          toReport.Power.EstimatedLoad,
+		LoadDelta:       toReport.Power.EstimatedLoad - fromReport.Power.EstimatedLoad,
+		FromRecommended: fromReport.Power.RecommendedWattage,
+		ToRecommended:   toReport.Power.RecommendedWattage,
+		FromVerdict:     fromReport.Power.Verdict,
+		ToVerdict:       toReport.Power.Verdict,
+	}
+
+	diff.Compatibility = CompatibilityDelta{
+		From:      summarizeReport(fromReport),
+		To:        summarizeReport(toReport),
+		NewIssues: failingOnlyIn(toReport, fromReport),
+		Resolved:  failingOnlyIn(fromReport, toReport),
+	}
+
+	return diff, nil
+}
+
+// evaluateBuild checks the active components of a saved build
+func (s *BuildDiffService) evaluateBuild(build models.Build) (*CompatibilityReport, error) {
+	slots := make([]repositories.BuildSlot, len(build.Components))
+	for i, slot := range build.Components {
+		slots[i] = repositories.BuildSlot{ComponentID: slot.ComponentID, Quantity: slot.Quantity}
+	}
+
+	report, _, err := s.compatibility.Check(slots)
+	return report, err
+}
+
+func diffComponents(build models.Build) []DiffComponent {
+	components := []DiffComponent{}
+	for _, slot := range build.Components {
+		component := DiffComponent{ComponentID: slot.ComponentID, Name: slot.ComponentID, Quantity: slot.Quantity}
+		if slot.Component != nil {
+			component.Name = slot.Component.Name
+			component.CategoryID = slot.Component.CategoryID
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// diffTotals pairs the totals by currency, a currency missing on one side counts as 0
+func diffTotals(from, to models.Price) []PriceDelta {
+	deltas := []PriceDelta{}
+	indexByCurrency := make(map[string]int)
+
+	for _, item := range from {
+		indexByCurrency[item.Currency] = len(deltas)
+		deltas = append(deltas, PriceDelta{Currency: item.Currency, Symbol: item.Symbol, From: item.Amount})
+	}
+
+	for _, item := range to {
+		i, exists := indexByCurrency[item.Currency]
+		if !exists {
+			i = len(deltas)
+			indexByCurrency[item.Currency] = i
+			deltas = append(deltas, PriceDelta{Currency: item.Currency, Symbol: item.Symbol})
+		}
+		deltas[i].To = item.Amount
+	}
+
+	for i := range deltas {
+		deltas[i].Delta = deltas[i].To - deltas[i].From
+	}
+
+	return deltas
+}
+
+func summarizeReport(report *CompatibilityReport) CompatibilitySummary {
+	return CompatibilitySummary{
+		Compatible:   report.Compatible,
+		ErrorCount:   report.ErrorCount,
+		WarningCount: report.WarningCount,
+	}
+}
+
+// failingOnlyIn returns the failures of report that have no equivalent failure in other,
+// matching on rule and involved components
+func failingOnlyIn(report, other *CompatibilityReport) []RuleResult {
+	otherFailures := make(map[string]bool)
+	for _, result := range other.Results {
+		if result.Status == StatusFail {
+			otherFailures[failureKey(result)] = true
+		}
+	}
+
+	failures := []RuleResult{}
+	for _, result := range report.Results {
+		if result.Status == StatusFail && !otherFailures[failureKey(result)] {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+func failureKey(result RuleResult) string {
+	// The PSU leads the power result, the drawing parts after it change with any edit
+	if result.RuleID == RulePSUWattage {
+		return result.RuleID + "|" + result.ComponentIDs[0]
+	}
+
+	ids := append([]string{}, result.ComponentIDs...)
+	sort.Strings(ids)
+	return result.RuleID + "|" + strings.Join(ids, ",")
+}
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"fmt"
+	"pc-builder/backend/api/models"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ResourceMemorySlots    = "memory_slots"
+	ResourceMemoryCapacity = "memory_capacity"
+	ResourceM2Slots        = "m2_slots"
+	ResourceSATAPorts      = "sata_ports"
+	ResourcePCIeX16Slots   = "pcie_x16_slots"
+	ResourcePCIeX4Slots    = "pcie_x4_slots"
+	ResourcePCIeX1Slots    = "pcie_x1_slots"
+)
+
+// pcieSlotTypes are the mainboard slot widths, narrowest first
+var pcieSlotTypes = []struct {
+	resource string
+	name     string
+	lanes    int
+}{
+	{ResourcePCIeX1Slots, "PCIe x1 slots", 1},
+	{ResourcePCIeX4Slots, "PCIe x4 slots", 4},
+	{ResourcePCIeX16Slots, "PCIe x16 slots", 16},
+}
+
+var pcieLanesPattern = regexp.MustCompile(`x(\d+)`)
+
+// ResourceUsage compares what the build consumes against what the mainboard provides
+type ResourceUsage struct {
+	Resource       string  `json:"resource"`
+	Used           float64 `json:"used"`
+	Available      float64 `json:"available"`
+	Unit           string  `json:"unit,omitempty"`
+	OverSubscribed bool    `json:"over_subscribed"`
+}
+
+type resourceDemand struct {
+	resource     string
+	name         string
+	boardSpec    string
+	unit         string
+	used         float64
+	componentIDs []string
+}
+
+// evaluateCapacityRules counts RAM sticks, memory size, drives and GPUs against the
+// mainboard slots. Nothing is reported without exactly one mainboard.
+func evaluateCapacityRules(parts []BuildPart) ([]ResourceUsage, []RuleResult) {
+	usages := []ResourceUsage{}
+	results := []RuleResult{}
+
+	mainboards := partsInCategory(parts, models.CategoryMainboard)
+	if len(mainboards) != 1 {
+		return usages, results
+	}
+	board := mainboards[0]
+
+	for _, demand := range resourceDemands(parts) {
+		if demand.used == 0 {
+			continue
+		}
+
+		result := RuleResult{
+			RuleID:       demand.resource,
+			Name:         demand.name,
+			Severity:     models.RuleSeverityError,
+			ComponentIDs: append([]string{board.ID}, demand.componentIDs...),
+		}
+
+		available, ok := models.ParseSpecNumber(board.SpecsMap[demand.boardSpec])
+		if !ok {
+			result.Status = StatusSkipped
+			result.Message = "Not enough spec data to verify this rule"
+			result.Details = fmt.Sprintf("%s is missing %s", board.Name, demand.boardSpec)
+			results = append(results, result)
+			continue
+		}
+
+		usage := ResourceUsage{
+			Resource:       demand.resource,
+			Used:           demand.used,
+			Available:      available,
+			Unit:           demand.unit,
+			OverSubscribed: demand.used > available,
+		}
+		usages = append(usages, usage)
+
+		result.Status = StatusPass
+		result.Details = fmt.Sprintf("%s of %s %s used", formatAmount(usage.Used), formatAmount(usage.Available), demand.unit)
+		if usage.OverSubscribed {
+			result.Status = StatusFail
+			result.Message = fmt.Sprintf("Build needs more %s than the mainboard provides", demand.name)
+		}
+		results = append(results, result)
+	}
+
+	pcieUsages, pcieResults := evaluatePCIeSlots(parts, board)
+	usages = append(usages, pcieUsages...)
+	results = append(results, pcieResults...)
+
+	results = append(results, checkM2Keys(parts, board)...)
+
+	return usages, results
+}
+
+func resourceDemands(parts []BuildPart) []resourceDemand {
+	memorySlots := resourceDemand{resource: ResourceMemorySlots, name: "memory slots", boardSpec: "dimm_slots", unit: "slots"}
+	memoryCapacity := resourceDemand{resource: ResourceMemoryCapacity, name: "memory capacity", boardSpec: "max_memory_gb", unit: "GB"}
+	m2Slots := resourceDemand{resource: ResourceM2Slots, name: "M.2 slots", boardSpec: "m2_slots", unit: "slots"}
+	sataPorts := resourceDemand{resource: ResourceSATAPorts, name: "SATA ports", boardSpec: "sata_ports", unit: "ports"}
+
+	for _, part := range parts {
+		quantity := float64(part.Quantity)
+
+		switch part.CategoryID {
+		case models.CategoryRAM:
+			modules, ok := models.ParseSpecNumber(part.SpecsMap["modules"])
+			if !ok || modules == 0 {
+				modules = 1
+			}
+			memorySlots.used += quantity * modules
+			memorySlots.componentIDs = append(memorySlots.componentIDs, part.ID)
+
+			if capacity, ok := models.ParseSpecNumber(part.SpecsMap["capacity_gb"]); ok {
+				memoryCapacity.used += quantity * capacity
+				memoryCapacity.componentIDs = append(memoryCapacity.componentIDs, part.ID)
+			}
+		case models.CategoryStorage:
+			if isAddInCard(part) {
+				continue
+			}
+			if isM2Drive(part) {
+				m2Slots.used += quantity
+				m2Slots.componentIDs = append(m2Slots.componentIDs, part.ID)
+			} else {
+				sataPorts.used += quantity
+				sataPorts.componentIDs = append(sataPorts.componentIDs, part.ID)
+			}
+		}
+	}
+
+	return []resourceDemand{memorySlots, memoryCapacity, m2Slots, sataPorts}
+}
+
+// evaluatePCIeSlots seats the expansion cards widest first, each in the narrowest free
+// slot it fits, so an x4 card takes an x16 slot only when the x4 slots are used up. Cards
+// left without a slot count against their own width. Boards that leave out pcie_x4_slots
+// or pcie_x1_slots have none of them.
+func evaluatePCIeSlots(parts []BuildPart, board BuildPart) ([]ResourceUsage, []RuleResult) {
+	usages := []ResourceUsage{}
+	results := []RuleResult{}
+
+	cards := []BuildPart{}
+	for _, part := range parts {
+		if pcieCardLanes(part) > 0 {
+			cards = append(cards, part)
+		}
+	}
+	if len(cards) == 0 {
+		return usages, results
+	}
+
+	if _, ok := models.ParseSpecNumber(board.SpecsMap[ResourcePCIeX16Slots]); !ok {
+		componentIDs := []string{board.ID}
+		for _, card := range cards {
+			componentIDs = append(componentIDs, card.ID)
+		}
+		results = append(results, RuleResult{
+			RuleID:       ResourcePCIeX16Slots,
+			Name:         "PCIe x16 slots",
+			Severity:     models.RuleSeverityError,
+			Status:       StatusSkipped,
+			ComponentIDs: componentIDs,
+			Message:      "Not enough spec data to verify this rule",
+			Details:      fmt.Sprintf("%s is missing %s", board.Name, ResourcePCIeX16Slots),
+		})
+		return usages, results
+	}
+
+	available := make([]float64, len(pcieSlotTypes))
+	used := make([]float64, len(pcieSlotTypes))
+	componentIDs := make([][]string, len(pcieSlotTypes))
+	for i, slotType := range pcieSlotTypes {
+		available[i], _ = models.ParseSpecNumber(board.SpecsMap[slotType.resource])
+	}
+
+	sort.SliceStable(cards, func(i, j int) bool {
+		return pcieCardLanes(cards[i]) > pcieCardLanes(cards[j])
+	})
+
+	for _, card := range cards {
+		fits := pcieSlotIndex(pcieCardLanes(card))
+		for n := 0; n < card.Quantity; n++ {
+			slot := fits
+			for i := fits; i < len(pcieSlotTypes); i++ {
+				if used[i] < available[i] {
+					slot = i
+					break
+				}
+			}
+
+			used[slot]++
+			if !slices.Contains(componentIDs[slot], card.ID) {
+				componentIDs[slot] = append(componentIDs[slot], card.ID)
+			}
+		}
+	}
+
+	for i, slotType := range pcieSlotTypes {
+		if used[i] == 0 {
+			continue
+		}
+
+		usage := ResourceUsage{
+			Resource:       slotType.resource,
+			Used:           used[i],
+			Available:      available[i],
+			Unit:           "slots",
+			OverSubscribed: used[i] > available[i],
+		}
+		usages = append(usages, usage)
+
+		result := RuleResult{
+			RuleID:       slotType.resource,
+			Name:         slotType.name,
+			Severity:     models.RuleSeverityError,
+			Status:       StatusPass,
+			ComponentIDs: append([]string{board.ID}, componentIDs[i]...),
+			Details:      fmt.Sprintf("%s of %s slots used", formatAmount(usage.Used), formatAmount(usage.Available)),
+		}
+		if usage.OverSubscribed {
+			result.Status = StatusFail
+			result.Message = fmt.Sprintf("Build needs more %s than the mainboard provides", slotType.name)
+		}
+		results = append(results, result)
+	}
+
+	return usages, results
+}
+
+// pcieCardLanes is the link width of an expansion card read from its interface, such as
+// "PCIe 4.0 x8". GPUs default to x16 and add-in card drives to x4, other parts are not
+// cards and give 0.
+func pcieCardLanes(part BuildPart) int {
+	defaultLanes := 0
+	switch {
+	case part.CategoryID == models.CategoryGPU:
+		defaultLanes = 16
+	case part.CategoryID == models.CategoryStorage && isAddInCard(part):
+		defaultLanes = 4
+	default:
+		return 0
+	}
+
+	match := pcieLanesPattern.FindStringSubmatch(strings.ToLower(part.SpecsMap["interface"]))
+	if match == nil {
+		return defaultLanes
+	}
+	lanes, err := strconv.Atoi(match[1])
+	if err != nil || lanes <= 0 {
+		return defaultLanes
+	}
+	return lanes
+}
+
+// pcieSlotIndex is the narrowest slot type a card of the given width fits
+func pcieSlotIndex(lanes int) int {
+	for i, slotType := range pcieSlotTypes {
+		if lanes <= slotType.lanes {
+			return i
+		}
+	}
+	return len(pcieSlotTypes) - 1
+}
+
+// checkM2Keys verifies each M.2 drive key (M when unspecified) against the board m2_keys
+func checkM2Keys(parts []BuildPart, board BuildPart) []RuleResult {
+	results := []RuleResult{}
+
+	boardKeys := board.SpecsMap["m2_keys"]
+	if boardKeys == "" {
+		return results
+	}
+
+	for _, drive := range partsInCategory(parts, models.CategoryStorage) {
+		if !isM2Drive(drive) {
+			continue
+		}
+
+		driveKey := drive.SpecsMap["m2_key"]
+		if driveKey == "" {
+			driveKey = "M"
+		}
+
+		result := RuleResult{
+			RuleID:       "m2_keys",
+			Name:         "M.2 key",
+			Severity:     models.RuleSeverityError,
+			Status:       StatusPass,
+			ComponentIDs: []string{board.ID, drive.ID},
+		}
+		if accepted, _ := compareSpecValues(models.RuleOperatorContains, boardKeys, driveKey); !accepted {
+			result.Status = StatusFail
+			result.Message = "Mainboard M.2 slots do not accept the drive key"
+			result.Details = fmt.Sprintf("drive key %q, mainboard keys %q", driveKey, boardKeys)
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func isM2Drive(drive BuildPart) bool {
+	layout := strings.ToLower(drive.SpecsMap["form_factor"] + " " + drive.SpecsMap["interface"])
+	return strings.Contains(layout, "m.2") || strings.Contains(layout, "m2") || strings.Contains(layout, "nvme")
+}
+
+// isAddInCard tells drives that sit in a PCIe slot instead of an M.2 slot or SATA port
+func isAddInCard(drive BuildPart) bool {
+	formFactor := strings.ToLower(drive.SpecsMap["form_factor"])
+	return strings.Contains(formFactor, "add-in") || strings.Contains(formFactor, "aic")
+}
+
+func formatAmount(amount float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", amount), "0"), ".")
+}
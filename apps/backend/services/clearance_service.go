@@ -0,0 +1,127 @@
+package services
+
+import (
+	"fmt"
+	"pc-builder/backend/api/models"
+	"strings"
+)
+
+// evaluateClearanceRules checks that the GPU, the air cooler and the AIO radiator
+// physically fit in the selected case. Lengths are read in millimetres.
+func evaluateClearanceRules(parts []BuildPart) []RuleResult {
+	results := []RuleResult{}
+
+	for _, pcCase := range partsInCategory(parts, models.CategoryCase) {
+		for _, gpu := range partsInCategory(parts, models.CategoryGPU) {
+			results = append(results, checkMaxDimension(
+				"gpu_length_clearance", "GPU length",
+				"Graphics card is too long for the case",
+				gpu, "length_mm", pcCase, "gpu_max_length_mm",
+			))
+		}
+
+		for _, cooler := range partsInCategory(parts, models.CategoryCooler) {
+			if isLiquidCooler(cooler) {
+				results = append(results, checkRadiatorSupport(cooler, pcCase))
+				continue
+			}
+
+			results = append(results, checkMaxDimension(
+				"cooler_height_clearance", "Cooler height",
+				"CPU cooler is too tall for the case",
+				cooler, "height_mm", pcCase, "cooler_max_height_mm",
+			))
+		}
+	}
+
+	return results
+}
+
+func checkMaxDimension(ruleID, name, message string, part BuildPart, partSpec string, pcCase BuildPart, caseSpec string) RuleResult {
+	result := RuleResult{
+		RuleID:       ruleID,
+		Name:         name,
+		Severity:     models.RuleSeverityError,
+		ComponentIDs: []string{part.ID, pcCase.ID},
+	}
+
+	size, sizeOK := models.ParseSpecNumber(part.SpecsMap[partSpec])
+	limit, limitOK := models.ParseSpecNumber(pcCase.SpecsMap[caseSpec])
+
+	switch {
+	case !sizeOK || !limitOK:
+		result.Status = StatusSkipped
+		result.Message = "Not enough spec data to verify this rule"
+		result.Details = fmt.Sprintf("requires %s on %s and %s on %s", partSpec, part.Name, caseSpec, pcCase.Name)
+	case size <= limit:
+		result.Status = StatusPass
+		result.Details = fmt.Sprintf("%.0fmm of %.0fmm available", size, limit)
+	default:
+		result.Status = StatusFail
+		result.Message = message
+		result.Details = fmt.Sprintf("%.0fmm needed, %.0fmm available", size, limit)
+	}
+
+	return result
+}
+
+func checkRadiatorSupport(cooler, pcCase BuildPart) RuleResult {
+	result := RuleResult{
+		RuleID:       "radiator_support",
+		Name:         "Radiator support",
+		Severity:     models.RuleSeverityError,
+		ComponentIDs: []string{cooler.ID, pcCase.ID},
+	}
+
+	size, sizeOK := models.ParseSpecNumber(cooler.SpecsMap["radiator_size_mm"])
+	supported := pcCase.SpecsMap["radiator_support"]
+
+	if !sizeOK || supported == "" {
+		result.Status = StatusSkipped
+		result.Message = "Not enough spec data to verify this rule"
+		result.Details = fmt.Sprintf("requires radiator_size_mm on %s and radiator_support on %s", cooler.Name, pcCase.Name)
+		return result
+	}
+
+	sized := false
+	for _, item := range splitSpecList(supported) {
+		mountable, ok := models.ParseSpecNumber(item)
+		sized = sized || ok
+		if ok && mountable == size {
+			result.Status = StatusPass
+			result.Details = fmt.Sprintf("%.0fmm radiator supported", size)
+			return result
+		}
+	}
+
+	// radiator_support kept as entered when it lists no size
+	if !sized {
+		result.Status = StatusSkipped
+		result.Message = "Not enough spec data to verify this rule"
+		result.Details = fmt.Sprintf("radiator_support %q on %s lists no size", supported, pcCase.Name)
+		return result
+	}
+
+	result.Status = StatusFail
+	result.Message = "Case cannot mount the AIO radiator"
+	result.Details = fmt.Sprintf("%.0fmm radiator, case supports %s", size, supported)
+	return result
+}
+
+// isLiquidCooler tells AIO coolers apart from air coolers using cooler_type,
+// falling back to the presence of a radiator size
+func isLiquidCooler(cooler BuildPart) bool {
+	coolerType := strings.ToLower(cooler.SpecsMap["cooler_type"])
+	for _, keyword := range []string{"aio", "liquid", "water"} {
+		if strings.Contains(coolerType, keyword) {
+			return true
+		}
+	}
+
+	if coolerType != "" {
+		return false
+	}
+
+	_, hasRadiator := cooler.SpecsMap["radiator_size_mm"]
+	return hasRadiator
+}
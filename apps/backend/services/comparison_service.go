@@ -0,0 +1,154 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const MaxComparedComponents = 6
+
+var ErrComparisonSize = fmt.Errorf("compare between 2 and %d components", MaxComparedComponents)
+var ErrComparedNotFound = errors.New("component not found")
+
+type ComparedComponent struct {
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	CategoryID      string            `json:"category_id"`
+	CategoryDisplay string            `json:"category_display"`
+	PrimaryBrand    string            `json:"primary_brand"`
+	ImageURL        json.RawMessage   `json:"image_url"`
+	InStock         bool              `json:"in_stock"`
+	Price           *models.PriceItem `json:"price"` // Nil when there is no price in the currency
+}
+
+// ComparisonRow holds one spec across the compared components, in their order. A nil
+// value means the component does not have the spec.
+type ComparisonRow struct {
+	SpecKey string    `json:"spec_key"`
+	Values  []*string `json:"values"`
+	Differs bool      `json:"differs"`
+}
+
+type ComponentComparison struct {
+	Currency        string              `json:"currency"`
+	MixedCategories bool                `json:"mixed_categories"`
+	Components      []ComparedComponent `json:"components"`
+	Rows            []ComparisonRow     `json:"rows"`
+	DifferingKeys   []string            `json:"differing_keys"`
+}
+
+type ComparisonService struct {
+	components *repositories.ComponentRepository
+}
+
+func NewComparisonService(db *gorm.DB) *ComparisonService {
+	return &ComparisonService{
+		components: repositories.NewComponentRepository(db),
+	}
+}
+
+// Compare aligns the specs of the components by key. The currency defaults to the first
+// price of the first component.
+func (s *ComparisonService) Compare(ids []string, currency string) (*ComponentComparison, error) {
+	if len(ids) < 2 || len(ids) > MaxComparedComponents {
+		return nil, ErrComparisonSize
+	}
+
+	components, err := s.components.GetComponentsByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(components) != len(ids) {
+		found := make(map[string]bool)
+		for _, component := range components {
+			found[component.ID] = true
+		}
+
+		missing := []string{}
+		for _, id := range ids {
+			if !found[id] {
+				missing = append(missing, id)
+			}
+		}
+		return nil, fmt.Errorf("%w: %s", ErrComparedNotFound, strings.Join(missing, ", "))
+	}
+
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		if prices := models.ParsePrice(components[0].Price); len(prices) > 0 {
+			currency = prices[0].Currency
+		}
+	}
+
+	comparison := &ComponentComparison{
+		Currency:      currency,
+		Components:    []ComparedComponent{},
+		Rows:          []ComparisonRow{},
+		DifferingKeys: []string{},
+	}
+
+	keys := []string{}
+	seenKeys := make(map[string]bool)
+	for _, component := range components {
+		if component.CategoryID != components[0].CategoryID {
+			comparison.MixedCategories = true
+		}
+
+		compared := ComparedComponent{
+			ID:              component.ID,
+			Name:            component.Name,
+			CategoryID:      component.CategoryID,
+			CategoryDisplay: component.CategoryDisplay,
+			PrimaryBrand:    primaryBrand(component),
+			ImageURL:        component.ImageURL,
+			InStock:         component.InStock,
+		}
+		if price, ok := models.ParsePrice(component.Price).Find(currency); ok {
+			compared.Price = &price
+		}
+		comparison.Components = append(comparison.Components, compared)
+
+		for key := range component.SpecsMap {
+			if !seenKeys[key] {
+				seenKeys[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		row := ComparisonRow{SpecKey: key, Values: make([]*string, len(components))}
+
+		for i, component := range components {
+			if value, exists := component.SpecsMap[key]; exists {
+				row.Values[i] = &value
+			}
+			if i > 0 && !sameSpecValue(row.Values[0], row.Values[i]) {
+				row.Differs = true
+			}
+		}
+
+		if row.Differs {
+			comparison.DifferingKeys = append(comparison.DifferingKeys, key)
+		}
+		comparison.Rows = append(comparison.Rows, row)
+	}
+
+	return comparison, nil
+}
+
+func sameSpecValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return normalizeSpecValue(*a) == normalizeSpecValue(*b)
+}
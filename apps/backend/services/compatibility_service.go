@@ -0,0 +1,408 @@
+package services
+
+import (
+	"fmt"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	StatusPass    = "pass"
+	StatusFail    = "fail"
+	StatusSkipped = "skipped"
+
+	RulePSUWattage = "psu_wattage"
+
+	// compatibilityBatchSize is how many candidates a compatible_with listing loads at a time
+	compatibilityBatchSize = 200
+)
+
+type CompatibilityService struct {
+	components *repositories.ComponentRepository
+	rules      *repositories.CompatibilityRuleRepository
+}
+
+func NewCompatibilityService(db *gorm.DB) *CompatibilityService {
+	return &CompatibilityService{
+		components: repositories.NewComponentRepository(db),
+		rules:      repositories.NewCompatibilityRuleRepository(db),
+	}
+}
+
+// BuildPart is a loaded component together with the quantity used in the build
+type BuildPart struct {
+	models.ComponentWithRelations
+	Quantity int `json:"quantity"`
+}
+
+type RuleResult struct {
+	RuleID       string   `json:"rule_id"`
+	Name         string   `json:"name"`
+	Severity     string   `json:"severity"`
+	Status       string   `json:"status"`
+	Message      string   `json:"message"`
+	Details      string   `json:"details,omitempty"`
+	ComponentIDs []string `json:"component_ids"`
+}
+
+type CompatibilityReport struct {
+	Compatible   bool            `json:"compatible"` // False when any error-severity rule fails
+	ErrorCount   int             `json:"error_count"`
+	WarningCount int             `json:"warning_count"`
+	Results      []RuleResult    `json:"results"`
+	Resources    []ResourceUsage `json:"resources"`
+	Power        *PowerEstimate  `json:"power"`
+}
+
+// singlePartCategories can appear at most once in a build
+var singlePartCategories = []string{
+	models.CategoryCPU,
+	models.CategoryMainboard,
+	models.CategoryCase,
+	models.CategoryPSU,
+	models.CategoryCooler,
+}
+
+// LoadParts resolves build slots into parts, returning the IDs that could not be found
+func (s *CompatibilityService) LoadParts(slots []repositories.BuildSlot) ([]BuildPart, []string, error) {
+	slots = repositories.NormalizeBuildSlots(slots)
+	if len(slots) == 0 {
+		return []BuildPart{}, []string{}, nil
+	}
+
+	ids := make([]string, len(slots))
+	for i, slot := range slots {
+		ids[i] = slot.ComponentID
+	}
+
+	components, err := s.components.GetComponentsByIDs(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[string]models.ComponentWithRelations)
+	for _, component := range components {
+		byID[component.ID] = component
+	}
+
+	parts := []BuildPart{}
+	missing := []string{}
+	for _, slot := range slots {
+		component, exists := byID[slot.ComponentID]
+		if !exists {
+			missing = append(missing, slot.ComponentID)
+			continue
+		}
+		parts = append(parts, BuildPart{ComponentWithRelations: component, Quantity: slot.Quantity})
+	}
+
+	return parts, missing, nil
+}
+
+// Check loads the given slots and evaluates every compatibility rule against them
+func (s *CompatibilityService) Check(slots []repositories.BuildSlot) (*CompatibilityReport, []string, error) {
+	parts, missing, err := s.LoadParts(slots)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report, err := s.Evaluate(parts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return report, missing, nil
+}
+
+// Evaluate runs the active database rules against already loaded parts
+func (s *CompatibilityService) Evaluate(parts []BuildPart) (*CompatibilityReport, error) {
+	rules, err := s.rules.GetActiveRules()
+	if err != nil {
+		return nil, err
+	}
+
+	return EvaluateWithRules(parts, rules), nil
+}
+
+// IncompatibleComponentIDs returns the listed components matching the filters that cause an
+// error-severity failure when added to the partial build. A candidate of a single-part
+// category is checked as a replacement of the selected part, not an addition, and only
+// against the rules involving its category. Candidates are loaded compatibilityBatchSize
+// at a time, so every matching component is checked however many there are.
+func (s *CompatibilityService) IncompatibleComponentIDs(parts []BuildPart, filters repositories.ComponentFilter) ([]string, error) {
+	rules, err := s.rules.GetActiveRules()
+	if err != nil {
+		return nil, err
+	}
+
+	rulesByCategory := make(map[string][]models.CompatibilityRule)
+	for _, rule := range rules {
+		rulesByCategory[rule.SourceCategoryID] = append(rulesByCategory[rule.SourceCategoryID], rule)
+		if rule.TargetCategoryID != rule.SourceCategoryID {
+			rulesByCategory[rule.TargetCategoryID] = append(rulesByCategory[rule.TargetCategoryID], rule)
+		}
+	}
+
+	incompatible := []string{}
+	afterID := ""
+	for {
+		candidates, err := s.components.GetCompatibilityCandidates(filters, afterID, compatibilityBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		incompatible = append(incompatible, incompatibleCandidates(parts, candidates, rulesByCategory)...)
+		if len(candidates) < compatibilityBatchSize {
+			return incompatible, nil
+		}
+		afterID = candidates[len(candidates)-1].ID
+	}
+}
+
+// incompatibleCandidates checks one batch of candidates against the partial build
+func incompatibleCandidates(parts []BuildPart, candidates []models.ComponentWithRelations, rulesByCategory map[string][]models.CompatibilityRule) []string {
+	incompatible := []string{}
+	for _, candidate := range candidates {
+		replacesPart := slices.Contains(singlePartCategories, candidate.CategoryID)
+
+		trial := []BuildPart{}
+		for _, part := range parts {
+			if part.ID == candidate.ID || (replacesPart && part.CategoryID == candidate.CategoryID) {
+				continue
+			}
+			trial = append(trial, part)
+		}
+		trial = append(trial, BuildPart{ComponentWithRelations: candidate, Quantity: 1})
+
+		report := EvaluateWithRules(trial, rulesByCategory[candidate.CategoryID])
+		for _, result := range report.Results {
+			if result.Status != StatusFail || result.Severity != models.RuleSeverityError ||
+				!slices.Contains(result.ComponentIDs, candidate.ID) {
+				continue
+			}
+			if result.RuleID == RulePSUWattage && !overdraws(report.Power, candidate.ID) {
+				continue
+			}
+
+			incompatible = append(incompatible, candidate.ID)
+			break
+		}
+	}
+
+	return incompatible
+}
+
+// overdraws tells whether the PSU could supply the load without the component, so the
+// component is the one pushing it over. The PSU itself always counts.
+func overdraws(power *PowerEstimate, componentID string) bool {
+	if power.PSUComponentID == componentID {
+		return true
+	}
+
+	for _, draw := range power.Breakdown {
+		if draw.ComponentID == componentID {
+			return power.EstimatedLoad-draw.Watts <= power.PSUWattage
+		}
+	}
+	return false
+}
+
+func (s *CompatibilityService) ActiveRules() ([]models.CompatibilityRule, error) {
+	return s.rules.GetActiveRules()
+}
+
+// EvaluateWithRules lets callers checking many candidate builds load the rules only once
+func EvaluateWithRules(parts []BuildPart, rules []models.CompatibilityRule) *CompatibilityReport {
+	results := evaluateQuantityRules(parts)
+
+	for _, rule := range rules {
+		results = append(results, evaluateRule(rule, parts)...)
+	}
+
+	results = append(results, evaluateClearanceRules(parts)...)
+
+	resources, capacityResults := evaluateCapacityRules(parts)
+	results = append(results, capacityResults...)
+
+	power := EstimatePower(parts, DefaultHeadroomPercent)
+	if result, ok := evaluatePowerRule(power); ok {
+		results = append(results, result)
+	}
+
+	report := newCompatibilityReport(results)
+	report.Resources = resources
+	report.Power = power
+	return report
+}
+
+func newCompatibilityReport(results []RuleResult) *CompatibilityReport {
+	report := &CompatibilityReport{
+		Compatible: true,
+		Results:    results,
+	}
+
+	for _, result := range results {
+		if result.Status != StatusFail {
+			continue
+		}
+
+		if result.Severity == models.RuleSeverityError {
+			report.Compatible = false
+			report.ErrorCount++
+		} else {
+			report.WarningCount++
+		}
+	}
+
+	return report
+}
+
+func evaluateQuantityRules(parts []BuildPart) []RuleResult {
+	results := []RuleResult{}
+
+	for _, category := range singlePartCategories {
+		matching := partsInCategory(parts, category)
+		if len(matching) == 0 {
+			continue
+		}
+
+		count := 0
+		componentIDs := []string{}
+		for _, part := range matching {
+			count += part.Quantity
+			componentIDs = append(componentIDs, part.ID)
+		}
+
+		result := RuleResult{
+			RuleID:       "single_" + category,
+			Name:         "Single " + category,
+			Severity:     models.RuleSeverityError,
+			Status:       StatusPass,
+			ComponentIDs: componentIDs,
+		}
+		if count > 1 {
+			result.Status = StatusFail
+			result.Message = fmt.Sprintf("A build can only contain one %s", category)
+			result.Details = fmt.Sprintf("%d selected", count)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func evaluatePowerRule(power *PowerEstimate) (RuleResult, bool) {
+	if power.Verdict == VerdictUnknown {
+		return RuleResult{}, false
+	}
+
+	result := RuleResult{
+		RuleID:       RulePSUWattage,
+		Name:         "PSU wattage",
+		Severity:     models.RuleSeverityError,
+		Status:       StatusPass,
+		ComponentIDs: []string{power.PSUComponentID},
+		Details: fmt.Sprintf("estimated load %.0fW, recommended %.0fW, PSU %.0fW",
+			power.EstimatedLoad, power.RecommendedWattage, power.PSUWattage),
+	}
+
+	// Every part drawing power shares the blame for an overloaded PSU
+	if power.Verdict != VerdictPass {
+		for _, draw := range power.Breakdown {
+			result.ComponentIDs = append(result.ComponentIDs, draw.ComponentID)
+		}
+	}
+
+	switch power.Verdict {
+	case VerdictFail:
+		result.Status = StatusFail
+		result.Message = "PSU cannot supply the estimated load"
+	case VerdictWarn:
+		result.Status = StatusFail
+		result.Severity = models.RuleSeverityWarning
+		result.Message = "PSU leaves less headroom than recommended"
+	}
+
+	return result, true
+}
+
+func evaluateRule(rule models.CompatibilityRule, parts []BuildPart) []RuleResult {
+	results := []RuleResult{}
+
+	for _, source := range partsInCategory(parts, rule.SourceCategoryID) {
+		for _, target := range partsInCategory(parts, rule.TargetCategoryID) {
+			result := RuleResult{
+				RuleID:       rule.ID,
+				Name:         rule.Name,
+				Severity:     rule.Severity,
+				ComponentIDs: []string{source.ID, target.ID},
+			}
+
+			sourceValue := source.SpecsMap[rule.SourceSpecKey]
+			targetValue := target.SpecsMap[rule.TargetSpecKey]
+
+			matches, comparable := compareSpecValues(rule.Operator, sourceValue, targetValue)
+
+			switch {
+			case sourceValue == "" || targetValue == "":
+				result.Status = StatusSkipped
+				result.Message = "Not enough spec data to verify this rule"
+				result.Details = missingSpecDetails(rule, source, target)
+			case !comparable:
+				result.Status = StatusSkipped
+				result.Message = "Not enough spec data to verify this rule"
+				result.Details = unreadableSpecDetails(rule, source, target)
+			case matches:
+				result.Status = StatusPass
+			default:
+				result.Status = StatusFail
+				result.Message = rule.Message
+				result.Details = fmt.Sprintf("%s %s %q vs %s %s %q",
+					source.CategoryID, rule.SourceSpecKey, sourceValue,
+					target.CategoryID, rule.TargetSpecKey, targetValue)
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+func missingSpecDetails(rule models.CompatibilityRule, source, target BuildPart) string {
+	missing := []string{}
+	if source.SpecsMap[rule.SourceSpecKey] == "" {
+		missing = append(missing, fmt.Sprintf("%s is missing %s", source.Name, rule.SourceSpecKey))
+	}
+	if target.SpecsMap[rule.TargetSpecKey] == "" {
+		missing = append(missing, fmt.Sprintf("%s is missing %s", target.Name, rule.TargetSpecKey))
+	}
+	return strings.Join(missing, ", ")
+}
+
+// unreadableSpecDetails names the values a numeric rule could not read as a number
+func unreadableSpecDetails(rule models.CompatibilityRule, source, target BuildPart) string {
+	unreadable := []string{}
+	if _, ok := models.ParseSpecNumber(source.SpecsMap[rule.SourceSpecKey]); !ok {
+		unreadable = append(unreadable, fmt.Sprintf("%s %s %q is not a number", source.Name, rule.SourceSpecKey, source.SpecsMap[rule.SourceSpecKey]))
+	}
+	if _, ok := models.ParseSpecNumber(target.SpecsMap[rule.TargetSpecKey]); !ok {
+		unreadable = append(unreadable, fmt.Sprintf("%s %s %q is not a number", target.Name, rule.TargetSpecKey, target.SpecsMap[rule.TargetSpecKey]))
+	}
+	return strings.Join(unreadable, ", ")
+}
+
+func partsInCategory(parts []BuildPart, category string) []BuildPart {
+	matching := []BuildPart{}
+	for _, part := range parts {
+		if part.CategoryID == category {
+			matching = append(matching, part)
+		}
+	}
+	return matching
+}
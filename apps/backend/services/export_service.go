@@ -0,0 +1,277 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ExportFormatMarkdown = "markdown"
+	ExportFormatCSV      = "csv"
+	ExportFormatBBCode   = "bbcode"
+	ExportFormatText     = "text"
+)
+
+var ErrUnknownExportFormat = errors.New("unknown export format")
+
+// ExportLine is one build slot as it appears in a parts list
+type ExportLine struct {
+	ComponentID string
+	Category    string
+	Name        string
+	Brand       string
+	Quantity    int
+	UnitPrice   *models.PriceItem // Nil when the component has no price in the export currency
+}
+
+type BuildExport struct {
+	Name     string
+	Currency string
+	Lines    []ExportLine
+	Total    models.PriceItem
+	Items    int
+	Unpriced int // Lines left out of the total
+}
+
+type ExportService struct {
+	components *repositories.ComponentRepository
+}
+
+func NewExportService(db *gorm.DB) *ExportService {
+	return &ExportService{
+		components: repositories.NewComponentRepository(db),
+	}
+}
+
+// PrepareExport resolves category and primary brand names of every slot and prices them
+// in currency, defaulting to the first currency of the build totals
+func (s *ExportService) PrepareExport(build *models.BuildWithTotals, currency string) (*BuildExport, error) {
+	if currency == "" && len(build.Totals) > 0 {
+		currency = build.Totals[0].Currency
+	}
+
+	ids := make([]string, len(build.Components))
+	for i, slot := range build.Components {
+		ids[i] = slot.ComponentID
+	}
+
+	components, err := s.components.GetComponentsByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]models.ComponentWithRelations)
+	for _, component := range components {
+		byID[component.ID] = component
+	}
+
+	export := &BuildExport{
+		Name:     build.Name,
+		Currency: strings.ToUpper(currency),
+		Lines:    []ExportLine{},
+		Total:    models.PriceItem{Currency: strings.ToUpper(currency)},
+	}
+
+	for _, slot := range build.Components {
+		line := ExportLine{ComponentID: slot.ComponentID, Name: slot.ComponentID, Quantity: slot.Quantity}
+
+		// Inactive components are not returned by the repository, fall back to the preloaded slot
+		if component, exists := byID[slot.ComponentID]; exists {
+			line.Category = component.CategoryDisplay
+			line.Name = component.Name
+			line.Brand = primaryBrand(component)
+		} else if slot.Component != nil {
+			line.Name = slot.Component.Name
+			if slot.Component.Category != nil {
+				line.Category = slot.Component.Category.DisplayName
+			}
+		}
+
+		if slot.Component != nil {
+			if price, ok := models.ParsePrice(slot.Component.Price).Find(export.Currency); ok {
+				line.UnitPrice = &price
+				export.Total.Symbol = price.Symbol
+				export.Total.Amount += price.Amount * float64(slot.Quantity)
+			}
+		}
+		if line.UnitPrice == nil {
+			export.Unpriced++
+		}
+
+		export.Items += slot.Quantity
+		export.Lines = append(export.Lines, line)
+	}
+
+	return export, nil
+}
+
+// RenderExport formats the export and returns the content with its content type and
+// file extension
+func RenderExport(export *BuildExport, format string) (string, string, string, error) {
+	switch format {
+	case ExportFormatMarkdown:
+		return renderMarkdown(export), "text/markdown; charset=utf-8", "md", nil
+	case ExportFormatCSV:
+		content, err := renderCSV(export)
+		return content, "text/csv; charset=utf-8", "csv", err
+	case ExportFormatBBCode:
+		return renderBBCode(export), "text/plain; charset=utf-8", "txt", nil
+	case ExportFormatText:
+		return renderText(export), "text/plain; charset=utf-8", "txt", nil
+	default:
+		return "", "", "", ErrUnknownExportFormat
+	}
+}
+
+func renderMarkdown(export *BuildExport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", export.Name)
+	b.WriteString("| Category | Component | Brand | Qty | Price |\n")
+	b.WriteString("| --- | --- | --- | ---: | ---: |\n")
+
+	for _, line := range export.Lines {
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %s |\n",
+			escapeMarkdownCell(line.Category),
+			escapeMarkdownCell(line.Name),
+			escapeMarkdownCell(line.Brand),
+			line.Quantity,
+			linePrice(line),
+		)
+	}
+
+	fmt.Fprintf(&b, "| **Total** | | | **%d** | **%s** |\n", export.Items, formatExportPrice(export.Total))
+	if note := unpricedNote(export); note != "" {
+		fmt.Fprintf(&b, "\n_%s_\n", note)
+	}
+
+	return b.String()
+}
+
+func renderCSV(export *BuildExport) (string, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	rows := [][]string{{"Category", "Component", "Brand", "Quantity", "Unit Price", "Price", "Currency"}}
+	for _, line := range export.Lines {
+		unitPrice, price := "", ""
+		if line.UnitPrice != nil {
+			unitPrice = strconv.FormatFloat(line.UnitPrice.Amount, 'f', 2, 64)
+			price = strconv.FormatFloat(line.UnitPrice.Amount*float64(line.Quantity), 'f', 2, 64)
+		}
+
+		rows = append(rows, []string{
+			line.Category,
+			line.Name,
+			line.Brand,
+			strconv.Itoa(line.Quantity),
+			unitPrice,
+			price,
+			export.Currency,
+		})
+	}
+	rows = append(rows, []string{
+		"Total", "", "",
+		strconv.Itoa(export.Items),
+		"",
+		strconv.FormatFloat(export.Total.Amount, 'f', 2, 64),
+		export.Currency,
+	})
+
+	if err := writer.WriteAll(rows); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+func renderBBCode(export *BuildExport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[b]%s[/b]\n[list]\n", export.Name)
+
+	for _, line := range export.Lines {
+		fmt.Fprintf(&b, "[*][b]%s:[/b] %s - %s\n", line.Category, describeLine(line), linePrice(line))
+	}
+
+	fmt.Fprintf(&b, "[/list]\n[b]Total:[/b] %s\n", formatExportPrice(export.Total))
+	if note := unpricedNote(export); note != "" {
+		fmt.Fprintf(&b, "[i]%s[/i]\n", note)
+	}
+
+	return b.String()
+}
+
+func renderText(export *BuildExport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", export.Name)
+
+	for _, line := range export.Lines {
+		fmt.Fprintf(&b, "%s: %s - %s\n", line.Category, describeLine(line), linePrice(line))
+	}
+
+	fmt.Fprintf(&b, "\nTotal: %s\n", formatExportPrice(export.Total))
+	if note := unpricedNote(export); note != "" {
+		fmt.Fprintf(&b, "%s\n", note)
+	}
+
+	return b.String()
+}
+
+// describeLine gives "Name (Brand) x2" with the brand and quantity only when useful
+func describeLine(line ExportLine) string {
+	description := line.Name
+	if line.Brand != "" && !strings.HasPrefix(strings.ToLower(line.Name), strings.ToLower(line.Brand)) {
+		description += " (" + line.Brand + ")"
+	}
+	if line.Quantity > 1 {
+		description += fmt.Sprintf(" x%d", line.Quantity)
+	}
+	return description
+}
+
+func linePrice(line ExportLine) string {
+	if line.UnitPrice == nil {
+		return "-"
+	}
+
+	price := *line.UnitPrice
+	price.Amount *= float64(line.Quantity)
+	return formatExportPrice(price)
+}
+
+func formatExportPrice(price models.PriceItem) string {
+	amount := strconv.FormatFloat(price.Amount, 'f', 2, 64)
+	if price.Symbol != "" {
+		return price.Symbol + amount
+	}
+	return strings.TrimSpace(amount + " " + price.Currency)
+}
+
+func unpricedNote(export *BuildExport) string {
+	if export.Unpriced == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d item(s) have no %s price and are not included in the total", export.Unpriced, export.Currency)
+}
+
+func escapeMarkdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+// primaryBrand falls back to the first brand when none is marked primary
+func primaryBrand(component models.ComponentWithRelations) string {
+	if component.PrimaryBrand != "" {
+		return component.PrimaryBrand
+	}
+	if len(component.BrandDisplays) > 0 {
+		return component.BrandDisplays[0]
+	}
+	return ""
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"slices"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ProfileGaming      = "gaming"
+	ProfileWorkstation = "workstation"
+	ProfileOffice      = "office"
+
+	MaxGeneratedBuilds = 3
+)
+
+var (
+	ErrUnknownProfile    = errors.New("unknown build profile")
+	ErrUnknownCategory   = errors.New("allocation category is not generated")
+	ErrInvalidComponents = errors.New("invalid component ID")
+	ErrMissingPrice      = errors.New("component has no price in the budget currency")
+	ErrBudgetTooLow      = errors.New("budget does not cover the required components")
+	ErrNoCompatibleBuild = errors.New("no compatible build fits the budget")
+)
+
+// ProfileAllocations is the default share of the budget spent per category.
+// Categories missing from a profile are left out of the generated build.
+var ProfileAllocations = map[string]map[string]float64{
+	ProfileGaming: {
+		models.CategoryGPU:       0.38,
+		models.CategoryCPU:       0.20,
+		models.CategoryMainboard: 0.11,
+		models.CategoryRAM:       0.08,
+		models.CategoryStorage:   0.07,
+		models.CategoryPSU:       0.07,
+		models.CategoryCase:      0.05,
+		models.CategoryCooler:    0.04,
+	},
+	ProfileWorkstation: {
+		models.CategoryCPU:       0.30,
+		models.CategoryGPU:       0.20,
+		models.CategoryRAM:       0.14,
+		models.CategoryMainboard: 0.12,
+		models.CategoryStorage:   0.10,
+		models.CategoryPSU:       0.06,
+		models.CategoryCase:      0.04,
+		models.CategoryCooler:    0.04,
+	},
+	ProfileOffice: {
+		models.CategoryCPU:       0.30,
+		models.CategoryMainboard: 0.18,
+		models.CategoryStorage:   0.16,
+		models.CategoryRAM:       0.14,
+		models.CategoryCase:      0.12,
+		models.CategoryPSU:       0.10,
+	},
+}
+
+// generatorCategoryOrder picks the platform first so later parts are checked against
+// it, and the PSU last so it can be sized for everything else
+var generatorCategoryOrder = []string{
+	models.CategoryCPU,
+	models.CategoryMainboard,
+	models.CategoryRAM,
+	models.CategoryGPU,
+	models.CategoryCooler,
+	models.CategoryCase,
+	models.CategoryStorage,
+	models.CategoryPSU,
+}
+
+type GenerateOptions struct {
+	Budget       models.PriceItem
+	Profile      string
+	Allocation   map[string]float64           // Overrides the profile share of a category, 0 removes it
+	ComponentIDs []string                     // Components that must be part of every build
+	Specs        map[string]map[string]string // Category -> spec key -> required value
+	Count        int
+}
+
+type GeneratedBuild struct {
+	Components      []BuildPart          `json:"components"`
+	Total           models.PriceItem     `json:"total"`
+	RemainingBudget float64              `json:"remaining_budget"`
+	Compatibility   *CompatibilityReport `json:"compatibility"`
+}
+
+type BuildGeneratorService struct {
+	components    *repositories.ComponentRepository
+	compatibility *CompatibilityService
+}
+
+func NewBuildGeneratorService(db *gorm.DB) *BuildGeneratorService {
+	return &BuildGeneratorService{
+		components:    repositories.NewComponentRepository(db),
+		compatibility: NewCompatibilityService(db),
+	}
+}
+
+type pricedPart struct {
+	part  BuildPart
+	price float64
+}
+
+// Generate assembles up to options.Count builds. Each category takes the most expensive
+// compatible candidate within its share of the budget, and unspent money rolls over to
+// the next category. Later builds swap the anchor part (GPU, or CPU without a GPU).
+func (s *BuildGeneratorService) Generate(options GenerateOptions) ([]GeneratedBuild, error) {
+	allocation, err := resolveAllocation(options.Profile, options.Allocation)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := options.Budget.Currency
+
+	forced, missing, err := s.compatibility.LoadParts(slotsForIDs(options.ComponentIDs))
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidComponents, strings.Join(missing, ", "))
+	}
+
+	forcedTotal := 0.0
+	for _, part := range forced {
+		price, ok := models.ParsePrice(part.Price).Find(currency)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no %s price", ErrMissingPrice, part.ID, currency)
+		}
+		forcedTotal += price.Amount * float64(part.Quantity)
+		delete(allocation, part.CategoryID)
+	}
+
+	available := options.Budget.Amount - forcedTotal
+	if available < 0 {
+		return nil, ErrBudgetTooLow
+	}
+	allocation = normalizeAllocation(allocation)
+
+	categories := []string{}
+	for category := range allocation {
+		categories = append(categories, category)
+	}
+
+	candidates, err := s.loadCandidates(categories, currency, options.Specs)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.compatibility.ActiveRules()
+	if err != nil {
+		return nil, err
+	}
+
+	anchor := models.CategoryCPU
+	if _, exists := allocation[models.CategoryGPU]; exists {
+		anchor = models.CategoryGPU
+	}
+
+	count := options.Count
+	if count <= 0 {
+		count = 1
+	}
+	if count > MaxGeneratedBuilds {
+		count = MaxGeneratedBuilds
+	}
+
+	builds := []GeneratedBuild{}
+	excluded := make(map[string]bool)
+	for len(builds) < count {
+		parts, ok := assembleBuild(forced, allocation, available, candidates, excluded, rules)
+		if !ok {
+			break
+		}
+
+		total := models.PriceItem{Currency: currency, Symbol: options.Budget.Symbol}
+		for _, part := range parts {
+			price, _ := models.ParsePrice(part.Price).Find(currency)
+			total.Amount += price.Amount * float64(part.Quantity)
+			if part.CategoryID == anchor {
+				excluded[part.ID] = true
+			}
+		}
+
+		if total.Amount > options.Budget.Amount {
+			break
+		}
+
+		builds = append(builds, GeneratedBuild{
+			Components:      parts,
+			Total:           total,
+			RemainingBudget: options.Budget.Amount - total.Amount,
+			Compatibility:   EvaluateWithRules(parts, rules),
+		})
+
+		// A forced anchor part would only produce the same build again
+		if _, exists := allocation[anchor]; !exists {
+			break
+		}
+	}
+
+	if len(builds) == 0 {
+		return nil, ErrNoCompatibleBuild
+	}
+
+	return builds, nil
+}
+
+func assembleBuild(forced []BuildPart, allocation map[string]float64, available float64, candidates map[string][]pricedPart, excluded map[string]bool, rules []models.CompatibilityRule) ([]BuildPart, bool) {
+	parts := append([]BuildPart{}, forced...)
+	spare := 0.0
+
+	for _, category := range generatorCategoryOrder {
+		share, exists := allocation[category]
+		if !exists {
+			continue
+		}
+
+		limit := share*available + spare
+		choice, found := pickCandidate(candidates[category], parts, limit, excluded, rules)
+		if !found {
+			return nil, false
+		}
+
+		parts = append(parts, choice.part)
+		spare = limit - choice.price
+	}
+
+	if !EvaluateWithRules(parts, rules).Compatible {
+		return nil, false
+	}
+
+	return parts, true
+}
+
+// pickCandidate returns the most expensive compatible candidate within limit, or the
+// cheapest compatible one when nothing fits. Candidates are sorted by price descending.
+func pickCandidate(candidates []pricedPart, parts []BuildPart, limit float64, excluded map[string]bool, rules []models.CompatibilityRule) (pricedPart, bool) {
+	for _, candidate := range candidates {
+		if candidate.price <= limit && !excluded[candidate.part.ID] && fitsBuild(candidate.part, parts, rules) {
+			return candidate, true
+		}
+	}
+
+	for i := len(candidates) - 1; i >= 0; i-- {
+		candidate := candidates[i]
+		if candidate.price > limit && !excluded[candidate.part.ID] && fitsBuild(candidate.part, parts, rules) {
+			return candidate, true
+		}
+	}
+
+	return pricedPart{}, false
+}
+
+func fitsBuild(candidate BuildPart, parts []BuildPart, rules []models.CompatibilityRule) bool {
+	report := EvaluateWithRules(append(append([]BuildPart{}, parts...), candidate), rules)
+	if !report.Compatible {
+		return false
+	}
+
+	// The generator only picks PSUs that cover the load plus the recommended headroom
+	if candidate.CategoryID == models.CategoryPSU {
+		return report.Power.Verdict == VerdictPass
+	}
+	return true
+}
+
+func (s *BuildGeneratorService) loadCandidates(categories []string, currency string, specs map[string]map[string]string) (map[string][]pricedPart, error) {
+	components, err := s.components.GetInStockComponents(categories)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[string][]pricedPart)
+	for _, component := range components {
+		price, ok := models.ParsePrice(component.Price).Find(currency)
+		if !ok || !matchesSpecConstraints(component.SpecsMap, specs[component.CategoryID]) {
+			continue
+		}
+
+		candidates[component.CategoryID] = append(candidates[component.CategoryID], pricedPart{
+			part:  BuildPart{ComponentWithRelations: component, Quantity: 1},
+			price: price.Amount,
+		})
+	}
+
+	for category := range candidates {
+		sort.SliceStable(candidates[category], func(i, j int) bool {
+			return candidates[category][i].price > candidates[category][j].price
+		})
+	}
+
+	return candidates, nil
+}
+
+func resolveAllocation(profile string, overrides map[string]float64) (map[string]float64, error) {
+	defaults, exists := ProfileAllocations[profile]
+	if !exists {
+		return nil, ErrUnknownProfile
+	}
+
+	allocation := make(map[string]float64)
+	for category, share := range defaults {
+		allocation[category] = share
+	}
+
+	for category, share := range overrides {
+		if !slices.Contains(generatorCategoryOrder, category) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCategory, category)
+		}
+		if share <= 0 {
+			delete(allocation, category)
+			continue
+		}
+		allocation[category] = share
+	}
+
+	return allocation, nil
+}
+
+// normalizeAllocation rescales the shares so they add up to 1
+func normalizeAllocation(allocation map[string]float64) map[string]float64 {
+	sum := 0.0
+	for _, share := range allocation {
+		sum += share
+	}
+
+	normalized := make(map[string]float64)
+	for category, share := range allocation {
+		normalized[category] = share / sum
+	}
+	return normalized
+}
+
+// matchesSpecConstraints compares like the listing spec filters: case-insensitive substring
+func matchesSpecConstraints(specs map[string]string, constraints map[string]string) bool {
+	for key, required := range constraints {
+		if !strings.Contains(normalizeSpecValue(specs[key]), normalizeSpecValue(required)) {
+			return false
+		}
+	}
+	return true
+}
+
+func slotsForIDs(ids []string) []repositories.BuildSlot {
+	slots := []repositories.BuildSlot{}
+	for _, id := range ids {
+		slots = append(slots, repositories.BuildSlot{ComponentID: id, Quantity: 1})
+	}
+	return slots
+}
@@ -0,0 +1,507 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"math"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ImportStatusMatched   = "matched"
+	ImportStatusUncertain = "uncertain"
+	ImportStatusUnmatched = "unmatched"
+
+	MaxImportLines = 100
+
+	// Confidence thresholds of a match, between them the match needs a user check
+	importMatchedConfidence   = 0.75
+	importUncertainConfidence = 0.5
+	importAlternatives        = 3
+)
+
+var ErrEmptyImport = errors.New("no parts found in the pasted text")
+var ErrTooManyImportLines = errors.New("too many lines in the pasted text")
+
+type ImportCandidate struct {
+	ComponentID string  `json:"component_id"`
+	Name        string  `json:"name"`
+	CategoryID  string  `json:"category_id"`
+	Brand       string  `json:"brand,omitempty"`
+	Confidence  float64 `json:"confidence"`
+}
+
+type ImportedLine struct {
+	LineNumber   int               `json:"line_number"`
+	Text         string            `json:"text"`
+	Quantity     int               `json:"quantity"`
+	Status       string            `json:"status"`
+	Confidence   float64           `json:"confidence"`
+	Match        *ImportCandidate  `json:"match,omitempty"`
+	Alternatives []ImportCandidate `json:"alternatives,omitempty"`
+}
+
+// ImportResult is a proposed build, Components holds the matched and uncertain lines
+// in the body format of POST /builds
+type ImportResult struct {
+	Name       string                   `json:"name"`
+	Lines      []ImportedLine           `json:"lines"`
+	Components []repositories.BuildSlot `json:"components"`
+	Matched    int                      `json:"matched"`
+	Uncertain  int                      `json:"uncertain"`
+	Unmatched  int                      `json:"unmatched"`
+}
+
+type ImportService struct {
+	components *repositories.ComponentRepository
+}
+
+func NewImportService(db *gorm.DB) *ImportService {
+	return &ImportService{
+		components: repositories.NewComponentRepository(db),
+	}
+}
+
+// parsedLine is a pasted line reduced to the part description
+type parsedLine struct {
+	number   int
+	text     string
+	query    string
+	category string // Category hint from a "CPU: ..." prefix or a CSV column
+	quantity int
+}
+
+type importCatalogEntry struct {
+	component  models.ComponentWithRelations
+	brand      string
+	nameTokens []string
+	allTokens  map[string]bool
+}
+
+var (
+	importTagPattern      = regexp.MustCompile(`\[/?[a-zA-Z*]+[^\]]*\]`)
+	importPricePattern    = regexp.MustCompile(`\s+[-–—]\s+\S*\d[\d.,]*\s*[A-Za-z]{0,3}$|\s+[-–—]\s+-$`)
+	importQuantityPattern = regexp.MustCompile(`(?i)(?:^(\d{1,2})\s*x\s+)|(?:\s+x\s*(\d{1,2})$)|(?:\s+\((\d{1,2})x?\)$)`)
+	importBulletPattern   = regexp.MustCompile(`^(?:[-*•+]|\d{1,2}[.)])\s+`)
+	importTokenPattern    = regexp.MustCompile(`[a-z0-9]+`)
+)
+
+// ParseImport matches every line of a plain-text, Markdown, BBCode or CSV parts list
+// against the catalog. Nothing is saved.
+func (s *ImportService) ParseImport(text string) (*ImportResult, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	categories, err := s.components.GetAllCategories()
+	if err != nil {
+		return nil, err
+	}
+	categoryIDs := make(map[string]string)
+	for _, category := range categories {
+		categoryIDs[strings.ToLower(category.ID)] = category.ID
+		categoryIDs[strings.ToLower(category.Name)] = category.ID
+		categoryIDs[strings.ToLower(category.DisplayName)] = category.ID
+	}
+
+	name, parsed := parseImportLines(lines, categoryIDs)
+	if len(parsed) == 0 {
+		return nil, ErrEmptyImport
+	}
+	if len(parsed) > MaxImportLines {
+		return nil, ErrTooManyImportLines
+	}
+
+	components, err := s.components.GetComponentsByCategories(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := make([]importCatalogEntry, len(components))
+	for i, component := range components {
+		catalog[i] = newImportCatalogEntry(component)
+	}
+
+	result := &ImportResult{
+		Name:       name,
+		Lines:      []ImportedLine{},
+		Components: []repositories.BuildSlot{},
+	}
+
+	for _, line := range parsed {
+		imported := matchImportLine(line, catalog)
+
+		switch imported.Status {
+		case ImportStatusMatched:
+			result.Matched++
+		case ImportStatusUncertain:
+			result.Uncertain++
+		default:
+			result.Unmatched++
+		}
+
+		if imported.Match != nil {
+			result.Components = append(result.Components, repositories.BuildSlot{
+				ComponentID: imported.Match.ComponentID,
+				Quantity:    imported.Quantity,
+			})
+		}
+		result.Lines = append(result.Lines, imported)
+	}
+	result.Components = repositories.NormalizeBuildSlots(result.Components)
+
+	return result, nil
+}
+
+// parseImportLines drops titles, headers, totals and markup, and returns the build name
+// found in a title line with the part lines
+func parseImportLines(lines []string, categoryIDs map[string]string) (string, []parsedLine) {
+	name := ""
+	parsed := []parsedLine{}
+
+	csvColumns := map[string]int{}
+	for i, raw := range lines {
+		text := strings.TrimSpace(raw)
+		if text == "" {
+			continue
+		}
+
+		// CSV header, as written by the CSV export or a spreadsheet
+		if len(csvColumns) == 0 && strings.Contains(text, ",") {
+			if columns := parseCSVHeader(text); len(columns) > 0 {
+				csvColumns = columns
+				continue
+			}
+		}
+
+		if title, ok := parseImportTitle(text); ok {
+			if name == "" && len(parsed) == 0 {
+				name = title
+			}
+			continue
+		}
+
+		line := parsedLine{number: i + 1, text: text, quantity: 1}
+		if len(csvColumns) > 0 {
+			if !parseCSVLine(text, csvColumns, &line) {
+				continue
+			}
+		} else if !parseTextLine(text, &line) {
+			continue
+		}
+
+		if line.category != "" {
+			line.category = categoryIDs[strings.ToLower(line.category)]
+		} else if prefix, rest, found := strings.Cut(line.query, ":"); found {
+			if categoryID, exists := categoryIDs[strings.ToLower(strings.TrimSpace(prefix))]; exists {
+				line.category = categoryID
+				line.query = strings.TrimSpace(rest)
+			}
+		}
+
+		if isImportNoise(line.query) {
+			continue
+		}
+		parsed = append(parsed, line)
+	}
+
+	return name, parsed
+}
+
+func parseImportTitle(text string) (string, bool) {
+	switch {
+	case strings.HasPrefix(text, "#"):
+		return strings.TrimSpace(strings.TrimLeft(text, "#")), true
+	case strings.HasPrefix(text, "[b]") && strings.HasSuffix(text, "[/b]") && !strings.Contains(text, ":"):
+		return strings.TrimSuffix(strings.TrimPrefix(text, "[b]"), "[/b]"), true
+	}
+	return "", false
+}
+
+func parseCSVHeader(text string) map[string]int {
+	fields, err := csv.NewReader(strings.NewReader(text)).Read()
+	if err != nil {
+		return nil
+	}
+
+	columns := map[string]int{}
+	for i, field := range fields {
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "component", "name", "part", "product":
+			columns["name"] = i
+		case "category", "type":
+			columns["category"] = i
+		case "brand", "manufacturer":
+			columns["brand"] = i
+		case "quantity", "qty", "count":
+			columns["quantity"] = i
+		}
+	}
+
+	if _, exists := columns["name"]; !exists {
+		return nil
+	}
+	return columns
+}
+
+func parseCSVLine(text string, columns map[string]int, line *parsedLine) bool {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	fields, err := reader.Read()
+	if err != nil {
+		return false
+	}
+
+	field := func(column string) string {
+		i, exists := columns[column]
+		if !exists || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	line.query = strings.TrimSpace(field("brand") + " " + field("name"))
+	line.category = field("category")
+	if quantity, err := strconv.Atoi(field("quantity")); err == nil && quantity > 0 {
+		line.quantity = quantity
+	}
+
+	return !strings.EqualFold(field("category"), "total") && field("name") != ""
+}
+
+func parseTextLine(text string, line *parsedLine) bool {
+	// Markdown table row: keep the category and component cells, the others are brand,
+	// quantity and price
+	if strings.HasPrefix(text, "|") {
+		cells := splitMarkdownRow(text)
+		if len(cells) == 0 || strings.Trim(strings.Join(cells, ""), "-: ") == "" {
+			return false
+		}
+		if len(cells) >= 4 {
+			if strings.EqualFold(cells[1], "component") {
+				return false
+			}
+			line.category = cells[0]
+			line.query = strings.TrimSpace(cells[2] + " " + cells[1])
+			if quantity, err := strconv.Atoi(cells[3]); err == nil && quantity > 0 {
+				line.quantity = quantity
+			}
+			return true
+		}
+		text = strings.Join(cells, " ")
+	}
+
+	text = importTagPattern.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(text, "**", "")
+	text = importBulletPattern.ReplaceAllString(strings.TrimSpace(text), "")
+	text = importPricePattern.ReplaceAllString(text, "")
+
+	if match := importQuantityPattern.FindStringSubmatch(text); match != nil {
+		for _, group := range match[1:] {
+			if quantity, err := strconv.Atoi(group); err == nil && quantity > 0 {
+				line.quantity = quantity
+			}
+		}
+		text = importQuantityPattern.ReplaceAllString(text, "")
+	}
+
+	line.query = strings.TrimSpace(text)
+	return line.query != ""
+}
+
+func splitMarkdownRow(text string) []string {
+	cells := []string{}
+	for _, cell := range strings.Split(strings.Trim(text, "|"), "|") {
+		cells = append(cells, strings.TrimSpace(strings.ReplaceAll(cell, "**", "")))
+	}
+	return cells
+}
+
+// isImportNoise recognizes total and note lines of exported lists
+func isImportNoise(query string) bool {
+	lower := strings.ToLower(strings.Trim(query, "_ "))
+	return lower == "" || strings.HasPrefix(lower, "total") || strings.Contains(lower, "not included in the total")
+}
+
+func newImportCatalogEntry(component models.ComponentWithRelations) importCatalogEntry {
+	entry := importCatalogEntry{
+		component:  component,
+		brand:      primaryBrand(component),
+		nameTokens: importTokens(component.Name + " " + component.Models),
+		allTokens:  map[string]bool{},
+	}
+
+	for _, token := range entry.nameTokens {
+		entry.allTokens[token] = true
+	}
+	for _, brand := range append(append([]string{}, component.BrandDisplays...), component.BrandNames...) {
+		for _, token := range importTokens(brand) {
+			entry.allTokens[token] = true
+		}
+	}
+
+	return entry
+}
+
+func matchImportLine(line parsedLine, catalog []importCatalogEntry) ImportedLine {
+	imported := ImportedLine{
+		LineNumber: line.number,
+		Text:       line.text,
+		Quantity:   line.quantity,
+		Status:     ImportStatusUnmatched,
+	}
+
+	queryTokens := importTokens(line.query)
+	if len(queryTokens) == 0 {
+		return imported
+	}
+
+	candidates := []ImportCandidate{}
+	for _, entry := range catalog {
+		confidence := scoreImportMatch(queryTokens, entry)
+		if line.category != "" && entry.component.CategoryID != line.category {
+			confidence *= 0.7
+		}
+		if confidence < importUncertainConfidence {
+			continue
+		}
+
+		candidates = append(candidates, ImportCandidate{
+			ComponentID: entry.component.ID,
+			Name:        entry.component.Name,
+			CategoryID:  entry.component.CategoryID,
+			Brand:       entry.brand,
+			Confidence:  math.Round(confidence*100) / 100,
+		})
+	}
+
+	if len(candidates) == 0 {
+		return imported
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+
+	best := candidates[0]
+	imported.Match = &best
+	imported.Confidence = best.Confidence
+	imported.Status = ImportStatusUncertain
+	if best.Confidence >= importMatchedConfidence {
+		imported.Status = ImportStatusMatched
+	}
+
+	alternatives := candidates[1:]
+	if len(alternatives) > importAlternatives {
+		alternatives = alternatives[:importAlternatives]
+	}
+	imported.Alternatives = alternatives
+
+	return imported
+}
+
+// scoreImportMatch weighs how much of the component name the line covers against how
+// much of the line the component explains. Model numbers (tokens with digits) must
+// match exactly, other tokens tolerate one typo.
+func scoreImportMatch(queryTokens []string, entry importCatalogEntry) float64 {
+	if len(entry.nameTokens) == 0 {
+		return 0
+	}
+
+	querySet := map[string]bool{}
+	for _, token := range queryTokens {
+		querySet[token] = true
+	}
+
+	nameCovered := 0.0
+	for _, token := range entry.nameTokens {
+		nameCovered += tokenMatch(token, querySet)
+	}
+
+	queryExplained := 0.0
+	missingModel := false
+	for _, token := range queryTokens {
+		credit := tokenMatch(token, entry.allTokens)
+		if credit == 0 && hasDigit(token) {
+			missingModel = true
+		}
+		queryExplained += credit
+	}
+
+	score := 0.6*nameCovered/float64(len(entry.nameTokens)) + 0.4*queryExplained/float64(len(queryTokens))
+
+	// A different model number (13900K for 14900K) is a different part
+	if missingModel {
+		score *= 0.6
+	}
+	return score
+}
+
+func tokenMatch(token string, tokens map[string]bool) float64 {
+	if tokens[token] {
+		return 1
+	}
+	if hasDigit(token) || len(token) < 5 {
+		return 0
+	}
+
+	for candidate := range tokens {
+		if !hasDigit(candidate) && withinOneEdit(token, candidate) {
+			return 0.8
+		}
+	}
+	return 0
+}
+
+func importTokens(value string) []string {
+	tokens := []string{}
+	seen := map[string]bool{}
+	for _, token := range importTokenPattern.FindAllString(strings.ToLower(value), -1) {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func hasDigit(value string) bool {
+	return strings.IndexFunc(value, unicode.IsDigit) >= 0
+}
+
+// withinOneEdit reports whether a and b differ by at most one insertion, deletion or
+// substitution
+func withinOneEdit(a, b string) bool {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	if len(a)-len(b) > 1 {
+		return false
+	}
+
+	i, j, edits := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			i++
+			j++
+			continue
+		}
+
+		edits++
+		if edits > 1 {
+			return false
+		}
+		if len(a) == len(b) {
+			j++
+		}
+		i++
+	}
+
+	return edits+(len(a)-i) <= 1
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"pc-builder/backend/api/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookSignatureHeader carries the hex HMAC-SHA256 of the body when a secret is configured
+const WebhookSignatureHeader = "X-PC-Builder-Signature"
+
+// AlertMessage is a triggered price alert ready for delivery
+type AlertMessage struct {
+	Alert     *models.PriceAlert `json:"alert"`
+	Recipient string             `json:"-"` // Email address of the subscriber
+	Subject   string             `json:"subject"`
+	Body      string             `json:"body"`
+	Target    string             `json:"target"` // Name of the watched component or build
+}
+
+// Notifier delivers price alerts through one channel
+type Notifier interface {
+	Name() string
+	Notify(message AlertMessage) error
+}
+
+// EmailOutboxNotifier queues the alert in the email_outbox table, a mail worker sends the
+// pending rows. Locally the queued emails can be read straight from the table.
+type EmailOutboxNotifier struct {
+	db *gorm.DB
+}
+
+func NewEmailOutboxNotifier(db *gorm.DB) *EmailOutboxNotifier {
+	return &EmailOutboxNotifier{db: db}
+}
+
+func (n *EmailOutboxNotifier) Name() string {
+	return "email"
+}
+
+func (n *EmailOutboxNotifier) Notify(message AlertMessage) error {
+	if message.Recipient == "" {
+		return fmt.Errorf("subscriber has no email address")
+	}
+
+	return n.db.Create(&models.EmailOutbox{
+		Recipient: message.Recipient,
+		Subject:   message.Subject,
+		Body:      message.Body,
+		AlertID:   &message.Alert.ID,
+		Status:    models.AlertStatusPending,
+	}).Error
+}
+
+// WebhookNotifier posts the alert as JSON to a fixed URL
+type WebhookNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n *WebhookNotifier) Notify(message AlertMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	if n.secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.secret))
+		mac.Write(payload)
+		request.Header.Set(WebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	BottleneckCPU      = "cpu"
+	BottleneckGPU      = "gpu"
+	BottleneckBalanced = "balanced"
+	BottleneckUnknown  = "unknown"
+
+	FPSSourceMeasured  = "measured"  // A stored fps: benchmark of the part
+	FPSSourceEstimated = "estimated" // Scaled from the game reference figures
+
+	DefaultResolution = "1080p"
+	DefaultPreset     = "high"
+
+	// Parts within this share of each other are reported as balanced
+	balancedTolerance = 0.1
+)
+
+var ErrMissingPerformanceParts = errors.New("build needs a CPU and a GPU to estimate performance")
+
+// ResolutionFactors scale the GPU-bound FPS from the 1080p reference
+var ResolutionFactors = map[string]float64{
+	"1080p": 1.0,
+	"1440p": 0.72,
+	"4k":    0.42,
+}
+
+// PresetFactors scale the GPU-bound FPS from the high preset reference
+var PresetFactors = map[string]float64{
+	"low":    1.6,
+	"medium": 1.3,
+	"high":   1.0,
+	"ultra":  0.8,
+}
+
+type PerformancePart struct {
+	ComponentID string  `json:"component_id"`
+	Name        string  `json:"name"`
+	Benchmark   string  `json:"benchmark,omitempty"`
+	Score       float64 `json:"score,omitempty"`
+}
+
+type GameEstimate struct {
+	GameID       string   `json:"game_id"`
+	Name         string   `json:"name"`
+	EstimatedFPS *float64 `json:"estimated_fps"` // Nil when a part has no usable benchmark
+	CPUFPS       *float64 `json:"cpu_fps"`
+	GPUFPS       *float64 `json:"gpu_fps"`
+	CPUSource    string   `json:"cpu_source,omitempty"`
+	GPUSource    string   `json:"gpu_source,omitempty"`
+	Bottleneck   string   `json:"bottleneck"`
+	Note         string   `json:"note,omitempty"`
+}
+
+type PerformanceReport struct {
+	BuildID    string          `json:"build_id"`
+	Resolution string          `json:"resolution"`
+	Preset     string          `json:"preset"`
+	CPU        PerformancePart `json:"cpu"`
+	GPU        PerformancePart `json:"gpu"`
+	Bottleneck string          `json:"bottleneck"` // Most frequent bottleneck across the games
+	Games      []GameEstimate  `json:"games"`
+}
+
+type PerformanceService struct {
+	games         *repositories.GameRepository
+	compatibility *CompatibilityService
+}
+
+func NewPerformanceService(db *gorm.DB) *PerformanceService {
+	return &PerformanceService{
+		games:         repositories.NewGameRepository(db),
+		compatibility: NewCompatibilityService(db),
+	}
+}
+
+// Estimate computes the FPS the CPU and the GPU can each drive in every game and keeps the
+// lower one. The CPU figure scales with its benchmark score, the GPU figure also with
+// the resolution and preset. A measured fps: benchmark of a part replaces its estimate.
+func (s *PerformanceService) Estimate(build *models.BuildWithTotals, gameIDs []string, resolution, preset string) (*PerformanceReport, error) {
+	slots := make([]repositories.BuildSlot, len(build.Components))
+	for i, slot := range build.Components {
+		slots[i] = repositories.BuildSlot{ComponentID: slot.ComponentID, Quantity: slot.Quantity}
+	}
+
+	parts, _, err := s.compatibility.LoadParts(slots)
+	if err != nil {
+		return nil, err
+	}
+
+	cpus := partsInCategory(parts, models.CategoryCPU)
+	gpus := partsInCategory(parts, models.CategoryGPU)
+	if len(cpus) == 0 || len(gpus) == 0 {
+		return nil, ErrMissingPerformanceParts
+	}
+	cpu, gpu := cpus[0], gpus[0]
+
+	games, err := s.games.GetActiveGames(gameIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PerformanceReport{
+		BuildID:    build.ID.String(),
+		Resolution: resolution,
+		Preset:     preset,
+		CPU:        PerformancePart{ComponentID: cpu.ID, Name: cpu.Name},
+		GPU:        PerformancePart{ComponentID: gpu.ID, Name: gpu.Name},
+		Bottleneck: BottleneckUnknown,
+		Games:      []GameEstimate{},
+	}
+
+	gpuFactor := ResolutionFactors[resolution] * PresetFactors[preset]
+	bottlenecks := make(map[string]int)
+
+	for _, game := range games {
+		estimate := GameEstimate{GameID: game.ID, Name: game.Name, Bottleneck: BottleneckUnknown}
+		fpsBenchmark := models.GameFPSBenchmark(game.ID, resolution, preset)
+
+		if fps, ok := benchmarkScore(cpu, fpsBenchmark); ok {
+			estimate.CPUFPS, estimate.CPUSource = roundedFPS(fps), FPSSourceMeasured
+		} else if score, ok := benchmarkScore(cpu, game.CPUBenchmark); ok && game.ReferenceCPUScore > 0 {
+			report.CPU.Benchmark, report.CPU.Score = game.CPUBenchmark, score
+			estimate.CPUFPS, estimate.CPUSource = roundedFPS(game.ReferenceCPUFPS*score/game.ReferenceCPUScore), FPSSourceEstimated
+		}
+
+		if fps, ok := benchmarkScore(gpu, fpsBenchmark); ok {
+			estimate.GPUFPS, estimate.GPUSource = roundedFPS(fps), FPSSourceMeasured
+		} else if score, ok := benchmarkScore(gpu, game.GPUBenchmark); ok && game.ReferenceGPUScore > 0 {
+			report.GPU.Benchmark, report.GPU.Score = game.GPUBenchmark, score
+			estimate.GPUFPS, estimate.GPUSource = roundedFPS(game.ReferenceGPUFPS*gpuFactor*score/game.ReferenceGPUScore), FPSSourceEstimated
+		}
+
+		switch {
+		case estimate.CPUFPS == nil && estimate.GPUFPS == nil:
+			estimate.Note = fmt.Sprintf("No %s or %s score for the CPU and GPU", game.CPUBenchmark, game.GPUBenchmark)
+		case estimate.CPUFPS == nil:
+			estimate.Note = fmt.Sprintf("No %s score for %s", game.CPUBenchmark, cpu.Name)
+		case estimate.GPUFPS == nil:
+			estimate.Note = fmt.Sprintf("No %s score for %s", game.GPUBenchmark, gpu.Name)
+		default:
+			cpuFPS, gpuFPS := *estimate.CPUFPS, *estimate.GPUFPS
+			estimate.EstimatedFPS = roundedFPS(math.Min(cpuFPS, gpuFPS))
+			estimate.Bottleneck = bottleneckOf(cpuFPS, gpuFPS)
+			bottlenecks[estimate.Bottleneck]++
+		}
+
+		report.Games = append(report.Games, estimate)
+	}
+
+	most := 0
+	for _, bottleneck := range []string{BottleneckGPU, BottleneckCPU, BottleneckBalanced} {
+		if bottlenecks[bottleneck] > most {
+			most = bottlenecks[bottleneck]
+			report.Bottleneck = bottleneck
+		}
+	}
+
+	return report, nil
+}
+
+// bottleneckOf names the part limiting the frame rate, the slower one unless they are close
+func bottleneckOf(cpuFPS, gpuFPS float64) string {
+	if math.Abs(cpuFPS-gpuFPS) <= balancedTolerance*math.Max(cpuFPS, gpuFPS) {
+		return BottleneckBalanced
+	}
+	if cpuFPS < gpuFPS {
+		return BottleneckCPU
+	}
+	return BottleneckGPU
+}
+
+// benchmarkScore returns the latest score of the benchmark, scores are sorted latest first
+func benchmarkScore(part BuildPart, name string) (float64, bool) {
+	for _, benchmark := range part.Benchmarks {
+		if benchmark.Name == name {
+			return benchmark.Score, true
+		}
+	}
+	return 0, false
+}
+
+func roundedFPS(fps float64) *float64 {
+	rounded := math.Round(fps)
+	return &rounded
+}
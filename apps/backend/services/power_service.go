@@ -0,0 +1,128 @@
+package services
+
+import (
+	"math"
+	"pc-builder/backend/api/models"
+)
+
+const (
+	VerdictPass    = "pass"
+	VerdictWarn    = "warn"
+	VerdictFail    = "fail"
+	VerdictUnknown = "unknown" // No PSU in the parts list
+
+	DefaultHeadroomPercent = 30.0
+
+	// Fallback draws in watts when a part has no usable power spec
+	defaultCPUWatts        = 95.0
+	defaultGPUWatts        = 200.0
+	mainboardWatts         = 50.0
+	ramStickWatts          = 5.0
+	driveWatts             = 10.0
+	fanWatts               = 3.0
+	coolerPumpWatts        = 10.0
+	recommendedWattageStep = 50.0
+)
+
+type PowerDraw struct {
+	ComponentID string  `json:"component_id"`
+	Name        string  `json:"name"`
+	CategoryID  string  `json:"category_id"`
+	Quantity    int     `json:"quantity"`
+	Watts       float64 `json:"watts"`  // Total for the slot, quantity included
+	Source      string  `json:"source"` // "spec" when read from the component, "estimate" otherwise
+}
+
+type PowerEstimate struct {
+	EstimatedLoad      float64     `json:"estimated_load"`
+	HeadroomPercent    float64     `json:"headroom_percent"`
+	RecommendedWattage float64     `json:"recommended_wattage"`
+	PSUComponentID     string      `json:"psu_component_id,omitempty"`
+	PSUWattage         float64     `json:"psu_wattage,omitempty"`
+	Verdict            string      `json:"verdict"`
+	Breakdown          []PowerDraw `json:"breakdown"`
+}
+
+// EstimatePower sums the draw of every part and compares it to the PSU wattage.
+// The verdict is pass when the PSU covers the load plus headroom, warn when it only
+// covers the raw load and fail when it cannot supply the load at all.
+func EstimatePower(parts []BuildPart, headroomPercent float64) *PowerEstimate {
+	estimate := &PowerEstimate{
+		HeadroomPercent: headroomPercent,
+		Breakdown:       []PowerDraw{},
+	}
+
+	for _, part := range parts {
+		if part.CategoryID == models.CategoryPSU {
+			if wattage, ok := maxSpecNumber(part.SpecsMap, "wattage"); ok && estimate.PSUComponentID == "" {
+				estimate.PSUComponentID = part.ID
+				estimate.PSUWattage = wattage
+			}
+			continue
+		}
+
+		watts, source := partWatts(part)
+		if watts == 0 {
+			continue
+		}
+
+		draw := PowerDraw{
+			ComponentID: part.ID,
+			Name:        part.Name,
+			CategoryID:  part.CategoryID,
+			Quantity:    part.Quantity,
+			Watts:       watts * float64(part.Quantity),
+			Source:      source,
+		}
+		estimate.EstimatedLoad += draw.Watts
+		estimate.Breakdown = append(estimate.Breakdown, draw)
+	}
+
+	withHeadroom := estimate.EstimatedLoad * (1 + headroomPercent/100)
+	estimate.RecommendedWattage = math.Ceil(withHeadroom/recommendedWattageStep) * recommendedWattageStep
+
+	switch {
+	case estimate.PSUComponentID == "":
+		estimate.Verdict = VerdictUnknown
+	case estimate.PSUWattage >= estimate.RecommendedWattage:
+		estimate.Verdict = VerdictPass
+	case estimate.PSUWattage >= estimate.EstimatedLoad:
+		estimate.Verdict = VerdictWarn
+	default:
+		estimate.Verdict = VerdictFail
+	}
+
+	return estimate
+}
+
+// partWatts returns the draw of a single unit of the part
+func partWatts(part BuildPart) (float64, string) {
+	switch part.CategoryID {
+	case models.CategoryCPU:
+		if watts, ok := maxSpecNumber(part.SpecsMap, "tdp_range", "tdp"); ok {
+			return watts, "spec"
+		}
+		return defaultCPUWatts, "estimate"
+	case models.CategoryGPU:
+		if watts, ok := maxSpecNumber(part.SpecsMap, "board_power", "tdp", "tdp_range"); ok {
+			return watts, "spec"
+		}
+		return defaultGPUWatts, "estimate"
+	case models.CategoryMainboard:
+		return mainboardWatts, "estimate"
+	case models.CategoryRAM:
+		return ramStickWatts, "estimate"
+	case models.CategoryStorage:
+		return driveWatts, "estimate"
+	case models.CategoryFan:
+		return fanWatts, "estimate"
+	case models.CategoryCooler:
+		fans, _ := specCount(part.SpecsMap, "fan_count")
+		return coolerPumpWatts + fans*fanWatts, "estimate"
+	case models.CategoryCase:
+		fans, _ := specCount(part.SpecsMap, "fan_count")
+		return fans * fanWatts, "estimate"
+	}
+
+	return 0, ""
+}
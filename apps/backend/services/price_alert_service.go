@@ -0,0 +1,311 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// alertDeliveryBatch is the number of pending alerts the delivery worker loads at once
+const alertDeliveryBatch = 50
+
+type PriceAlertService struct {
+	alerts    *repositories.PriceAlertRepository
+	builds    *repositories.BuildRepository
+	rates     *repositories.ExchangeRateRepository
+	notifiers []Notifier
+	wake      chan struct{}
+}
+
+func NewPriceAlertService(db *gorm.DB, notifiers ...Notifier) *PriceAlertService {
+	return &PriceAlertService{
+		alerts:    repositories.NewPriceAlertRepository(db),
+		builds:    repositories.NewBuildRepository(db),
+		rates:     repositories.NewExchangeRateRepository(db),
+		notifiers: notifiers,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// StartDelivery runs the delivery worker in the background. It sends the pending alerts as
+// soon as a price update queues them, and every interval to pick up the ones left pending
+// by a restart.
+func (s *PriceAlertService) StartDelivery(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.DeliverPending(); err != nil {
+				log.Printf("⚠️ Failed to deliver price alerts: %v", err)
+			}
+
+			select {
+			case <-s.wake:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// DeliverPending hands every pending alert to the notifiers until none is left
+func (s *PriceAlertService) DeliverPending() error {
+	for {
+		alerts, err := s.alerts.GetPendingAlerts(alertDeliveryBatch)
+		if err != nil {
+			return err
+		}
+		if len(alerts) == 0 {
+			return nil
+		}
+
+		for i := range alerts {
+			if err := s.deliver(&alerts[i]); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Notifiers lists the names of the configured delivery channels
+func (s *PriceAlertService) Notifiers() []string {
+	names := []string{}
+	for _, notifier := range s.notifiers {
+		names = append(names, notifier.Name())
+	}
+	return names
+}
+
+// CheckPriceChange stores an alert for the subscriptions of the component, and of the builds
+// containing it, whose watched amount went from at or above the target to below it. Derived
+// currencies are compared too. It runs after the price update is committed, a failing alert
+// never undoes the update. Delivery happens in the background so the request does not wait
+// for the notifiers.
+func (s *PriceAlertService) CheckPriceChange(componentID, componentName string, previous, current models.Price) error {
+	defer s.wakeDelivery()
+
+	rates, err := s.rates.GetExchangeRates()
+	if err != nil {
+		return err
+	}
+	previous = previous.Stored().WithDerived(rates)
+	current = current.Stored().WithDerived(rates)
+
+	var errs []error
+
+	for _, item := range current {
+		old, existed := previous.Find(item.Currency)
+		if existed && old.Amount == item.Amount {
+			continue
+		}
+
+		subscriptions, err := s.alerts.GetComponentSubscriptions(componentID, item.Currency)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, subscription := range subscriptions {
+			if !crossedBelow(old.Amount, existed, item.Amount, subscription.TargetPrice) {
+				continue
+			}
+			alert := newPriceAlert(subscription, &componentID, componentName, old.Amount, item.Amount)
+			errs = append(errs, s.alerts.CreateAlert(alert))
+		}
+
+		errs = append(errs, s.checkBuilds(componentID, item, old.Amount))
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkBuilds compares the current total of every watched build containing the component with
+// the total it had before the component price changed
+func (s *PriceAlertService) checkBuilds(componentID string, item models.PriceItem, oldAmount float64) error {
+	subscriptions, err := s.alerts.GetBuildSubscriptions(componentID, item.Currency)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, subscription := range subscriptions {
+		build, err := s.builds.GetBuildByID(*subscription.BuildID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		total, _ := build.Totals.Find(item.Currency)
+		quantity := 0
+		for _, slot := range build.Components {
+			if slot.ComponentID == componentID {
+				quantity += slot.Quantity
+			}
+		}
+		previousTotal := total.Amount - float64(quantity)*(item.Amount-oldAmount)
+
+		if !crossedBelow(previousTotal, true, total.Amount, subscription.TargetPrice) {
+			continue
+		}
+		alert := newPriceAlert(subscription, &componentID, build.Name, previousTotal, total.Amount)
+		errs = append(errs, s.alerts.CreateAlert(alert))
+	}
+
+	return errors.Join(errs...)
+}
+
+// CheckRateChange stores an alert for the subscriptions whose watched amount went from at or
+// above the target to below it because the exchange rates changed. Only derived amounts
+// follow the rates, a currency stored on the component never fires here.
+func (s *PriceAlertService) CheckRateChange(previousRates, currentRates []models.ExchangeRate) error {
+	defer s.wakeDelivery()
+
+	subscriptions, err := s.alerts.GetActiveSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	componentIDs := []string{}
+	for _, subscription := range subscriptions {
+		if subscription.ComponentID != nil {
+			componentIDs = append(componentIDs, *subscription.ComponentID)
+		}
+	}
+	components, err := s.alerts.GetSubscribedComponents(componentIDs)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]models.Component, len(components))
+	for _, component := range components {
+		byID[component.ID] = component
+	}
+
+	var errs []error
+	for _, subscription := range subscriptions {
+		var target string
+		var previous, current models.Price
+		var componentID *string
+
+		switch {
+		case subscription.ComponentID != nil:
+			component, exists := byID[*subscription.ComponentID]
+			if !exists {
+				continue
+			}
+			price := models.ParsePrice(component.Price).Stored()
+			target, componentID = component.Name, &component.ID
+			previous, current = price.WithDerived(previousRates), price.WithDerived(currentRates)
+		case subscription.BuildID != nil:
+			build, err := s.builds.GetBuildByID(*subscription.BuildID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			target = build.Name
+			previous, current = totalsWithRates(build.Components, previousRates), totalsWithRates(build.Components, currentRates)
+		default:
+			continue
+		}
+
+		old, existed := previous.Find(subscription.Currency)
+		item, exists := current.Find(subscription.Currency)
+		if !exists || !crossedBelow(old.Amount, existed, item.Amount, subscription.TargetPrice) {
+			continue
+		}
+		alert := newPriceAlert(subscription, componentID, target, old.Amount, item.Amount)
+		errs = append(errs, s.alerts.CreateAlert(alert))
+	}
+
+	return errors.Join(errs...)
+}
+
+// totalsWithRates sums the build slots with their prices derived from the given rates
+func totalsWithRates(slots []models.BuildComponent, rates []models.ExchangeRate) models.Price {
+	derived := make([]models.BuildComponent, 0, len(slots))
+	for _, slot := range slots {
+		if slot.Component == nil {
+			continue
+		}
+
+		price, err := json.Marshal(models.ParsePrice(slot.Component.Price).Stored().WithDerived(rates))
+		if err != nil {
+			continue
+		}
+		component := *slot.Component
+		component.Price = price
+		slot.Component = &component
+		derived = append(derived, slot)
+	}
+
+	return repositories.CalculateBuildTotals(derived)
+}
+
+// wakeDelivery tells the delivery worker there are new alerts, without waiting when it is
+// already busy
+func (s *PriceAlertService) wakeDelivery() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliver hands the alert to every notifier, the alert is failed when any of them fails
+func (s *PriceAlertService) deliver(alert *models.PriceAlert) error {
+	message := AlertMessage{
+		Alert:   alert,
+		Subject: fmt.Sprintf("Price drop: %s is now %s %.2f", alert.Target, alert.Currency, alert.Amount),
+		Body: fmt.Sprintf("%s dropped from %s %.2f to %s %.2f, below your target of %s %.2f.",
+			alert.Target, alert.Currency, alert.PreviousAmount, alert.Currency, alert.Amount, alert.Currency, alert.TargetPrice),
+		Target: alert.Target,
+	}
+	if alert.Subscription != nil && alert.Subscription.User != nil {
+		message.Recipient = alert.Subscription.User.Email
+	}
+
+	failures := []string{}
+	for _, notifier := range s.notifiers {
+		if err := notifier.Notify(message); err != nil {
+			log.Printf("⚠️ Failed to deliver price alert %d through %s: %v", alert.ID, notifier.Name(), err)
+			failures = append(failures, fmt.Sprintf("%s: %v", notifier.Name(), err))
+		}
+	}
+
+	return s.alerts.MarkAlertDelivered(alert, strings.Join(failures, "; "))
+}
+
+// crossedBelow reports whether the amount moved under the target with this change. A currency
+// the component did not have before counts as a crossing.
+func crossedBelow(previous float64, hadPrevious bool, current, target float64) bool {
+	if current >= target {
+		return false
+	}
+	return !hadPrevious || previous >= target
+}
+
+func newPriceAlert(subscription models.PriceAlertSubscription, componentID *string, target string, previous, current float64) *models.PriceAlert {
+	var buildID *uuid.UUID
+	if subscription.BuildID != nil {
+		id := *subscription.BuildID
+		buildID = &id
+	}
+
+	return &models.PriceAlert{
+		SubscriptionID: subscription.ID,
+		UserID:         subscription.UserID,
+		ComponentID:    componentID,
+		BuildID:        buildID,
+		Target:         target,
+		Currency:       subscription.Currency,
+		TargetPrice:    subscription.TargetPrice,
+		PreviousAmount: previous,
+		Amount:         current,
+		Status:         models.AlertStatusPending,
+	}
+}
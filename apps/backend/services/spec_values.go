@@ -0,0 +1,89 @@
+package services
+
+import (
+	"pc-builder/backend/api/models"
+	"strings"
+)
+
+// Spec values are free-form, so "LGA 1700", "lga-1700" and "LGA1700" must compare equal
+var specValueAliases = map[string]string{
+	"microatx":    "matx",
+	"uatx":        "matx",
+	"miniitx":     "itx",
+	"extendedatx": "eatx",
+}
+
+// compareSpecValues applies the rule operator. ok is false when a numeric operator gets a
+// value that is not a number, such as "N/A", so the rule cannot be verified.
+func compareSpecValues(operator, sourceValue, targetValue string) (matches bool, ok bool) {
+	switch operator {
+	case models.RuleOperatorEquals:
+		return normalizeSpecValue(sourceValue) == normalizeSpecValue(targetValue), true
+	case models.RuleOperatorContains:
+		supported := make(map[string]bool)
+		for _, value := range splitSpecList(sourceValue) {
+			supported[value] = true
+		}
+
+		for _, value := range splitSpecList(targetValue) {
+			if !supported[value] {
+				return false, true
+			}
+		}
+		return true, true
+	case models.RuleOperatorLTE, models.RuleOperatorGTE:
+		source, sourceOK := models.ParseSpecNumber(sourceValue)
+		target, targetOK := models.ParseSpecNumber(targetValue)
+		if !sourceOK || !targetOK {
+			return false, false
+		}
+
+		if operator == models.RuleOperatorLTE {
+			return source <= target, true
+		}
+		return source >= target, true
+	}
+
+	return false, true
+}
+
+func normalizeSpecValue(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	normalized = strings.NewReplacer(" ", "", "-", "", "_", "").Replace(normalized)
+
+	if alias, exists := specValueAliases[normalized]; exists {
+		return alias
+	}
+	return normalized
+}
+
+func splitSpecList(value string) []string {
+	values := []string{}
+	for _, item := range models.SplitSpecList(value) {
+		if normalized := normalizeSpecValue(item); normalized != "" {
+			values = append(values, normalized)
+		}
+	}
+	return values
+}
+
+// maxSpecNumber returns the largest number of the first spec key present on the part, so a
+// range such as "65-125W" gives its upper bound
+func maxSpecNumber(specs map[string]string, keys ...string) (float64, bool) {
+	for _, key := range keys {
+		if value, exists := specs[key]; exists {
+			if number, ok := models.ParseSpecMaxNumber(value); ok {
+				return number, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// specCount reads a count such as "3 x 120mm" from its first number, 0 when the key is missing
+func specCount(specs map[string]string, key string) (float64, bool) {
+	if value, exists := specs[key]; exists {
+		return models.ParseSpecNumber(value)
+	}
+	return 0, false
+}
@@ -0,0 +1,584 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"pc-builder/backend/api/models"
+	"pc-builder/backend/api/repositories"
+	"slices"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultUpgradeSuggestions = 5
+	MaxUpgradeSuggestions     = 10
+
+	// Best single upgrades per slot that are tried with an enabling part or paired together
+	upgradeCandidatesPerSlot = 15
+	upgradePairPool          = 10
+)
+
+var ErrEmptyBuild = errors.New("build has no components to upgrade")
+
+// upgradeWeights ranks improvements across categories, a faster GPU matters more than a
+// bigger drive
+var upgradeWeights = map[string]float64{
+	models.CategoryGPU:     1.0,
+	models.CategoryCPU:     0.8,
+	models.CategoryRAM:     0.4,
+	models.CategoryStorage: 0.3,
+}
+
+// upgradeEnablers are the categories that may be replaced alongside a part when the
+// untouched build cannot take it (new socket, longer card, higher draw)
+var upgradeEnablers = map[string][]string{
+	models.CategoryCPU: {models.CategoryMainboard, models.CategoryCooler, models.CategoryPSU},
+	models.CategoryGPU: {models.CategoryPSU, models.CategoryCase},
+}
+
+// specScorer rates a part from its specs. Scorers of a category are tried in order and the
+// first one both parts support is used, so scores are always computed the same way.
+type specScorer struct {
+	label string
+	score func(specs map[string]string) (float64, bool)
+}
+
+var upgradeScorers = map[string][]specScorer{
+	models.CategoryCPU: {
+		{label: "cores x boost clock", score: func(specs map[string]string) (float64, bool) {
+			return multiplySpecs(specs, []string{"cores"}, []string{"boost_clock", "base_clock"})
+		}},
+		{label: "core count", score: func(specs map[string]string) (float64, bool) {
+			return maxSpecNumber(specs, "cores")
+		}},
+	},
+	models.CategoryGPU: {
+		{label: "shaders x boost clock", score: func(specs map[string]string) (float64, bool) {
+			return multiplySpecs(specs, []string{"cuda_cores", "stream_processors", "shader_units"}, []string{"boost_clock", "base_clock"})
+		}},
+		{label: "VRAM", score: func(specs map[string]string) (float64, bool) {
+			return maxSpecNumber(specs, "vram_gb", "memory_size", "vram")
+		}},
+	},
+	models.CategoryRAM: {
+		{label: "capacity x speed", score: func(specs map[string]string) (float64, bool) {
+			return multiplySpecs(specs, []string{"capacity_gb"}, []string{"speed", "speed_mhz"})
+		}},
+		{label: "capacity", score: func(specs map[string]string) (float64, bool) {
+			return maxSpecNumber(specs, "capacity_gb")
+		}},
+	},
+	models.CategoryStorage: {
+		{label: "capacity", score: func(specs map[string]string) (float64, bool) {
+			return maxSpecNumber(specs, "capacity_gb")
+		}},
+	},
+}
+
+type UpgradeReplacement struct {
+	CategoryID string           `json:"category_id"`
+	From       *DiffComponent   `json:"from"` // Nil when the part is added rather than replaced
+	To         DiffComponent    `json:"to"`
+	Cost       models.PriceItem `json:"cost"`
+	ScoreFrom  float64          `json:"score_from,omitempty"`
+	ScoreTo    float64          `json:"score_to,omitempty"`
+	ScoreBasis string           `json:"score_basis,omitempty"`
+}
+
+type UpgradeSuggestion struct {
+	Rank               int                   `json:"rank"`
+	Replacements       []UpgradeReplacement  `json:"replacements"`
+	Cost               models.PriceItem      `json:"cost"`
+	ImprovementPercent float64               `json:"improvement_percent"` // Weighted by category
+	Reasons            []string              `json:"reasons"`
+	Compatibility      *CompatibilitySummary `json:"compatibility"`
+	Power              *PowerEstimate        `json:"power"`
+}
+
+type UpgradeAdvice struct {
+	BuildID     string              `json:"build_id"`
+	Budget      models.PriceItem    `json:"budget"`
+	Suggestions []UpgradeSuggestion `json:"suggestions"`
+}
+
+type UpgradeOptions struct {
+	Budget     models.PriceItem
+	Categories []string // Performance categories to upgrade, all when empty
+	Limit      int
+}
+
+type UpgradeService struct {
+	components    *repositories.ComponentRepository
+	compatibility *CompatibilityService
+}
+
+func NewUpgradeService(db *gorm.DB) *UpgradeService {
+	return &UpgradeService{
+		components:    repositories.NewComponentRepository(db),
+		compatibility: NewCompatibilityService(db),
+	}
+}
+
+// upgradeOption is a set of replaced slots, indexed in the build parts
+type upgradeOption struct {
+	replaced     map[int]BuildPart
+	replacements []UpgradeReplacement
+	cost         float64
+	improvement  float64
+	reasons      []string
+	report       *CompatibilityReport
+	enabled      bool // Needs an enabling part, so it cannot be paired with another upgrade
+}
+
+// Advise suggests the single-slot and two-slot replacements with the largest spec score
+// improvement that fit the budget and add no compatibility failure or PSU verdict
+// downgrade compared to the current build
+func (s *UpgradeService) Advise(build *models.BuildWithTotals, options UpgradeOptions) (*UpgradeAdvice, error) {
+	slots := make([]repositories.BuildSlot, len(build.Components))
+	for i, slot := range build.Components {
+		slots[i] = repositories.BuildSlot{ComponentID: slot.ComponentID, Quantity: slot.Quantity}
+	}
+
+	parts, _, err := s.compatibility.LoadParts(slots)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, ErrEmptyBuild
+	}
+
+	rules, err := s.compatibility.ActiveRules()
+	if err != nil {
+		return nil, err
+	}
+
+	categories := options.Categories
+	if len(categories) == 0 {
+		for category := range upgradeScorers {
+			categories = append(categories, category)
+		}
+	}
+
+	loadCategories := append([]string{}, categories...)
+	for _, category := range categories {
+		loadCategories = append(loadCategories, upgradeEnablers[category]...)
+	}
+
+	currency := options.Budget.Currency
+	candidates, err := s.loadUpgradeCandidates(loadCategories, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := EvaluateWithRules(parts, rules)
+
+	singles := []upgradeOption{}
+	for index, part := range parts {
+		if !slices.Contains(categories, part.CategoryID) {
+			continue
+		}
+
+		singles = append(singles, s.slotUpgrades(parts, index, candidates, options.Budget.Amount, rules, baseline)...)
+	}
+
+	sort.SliceStable(singles, func(i, j int) bool {
+		return betterUpgrade(singles[i], singles[j])
+	})
+
+	options.Budget.Symbol = budgetSymbol(options.Budget, candidates)
+	all := append([]upgradeOption{}, singles...)
+	all = append(all, pairUpgrades(parts, singles, options.Budget.Amount, rules, baseline)...)
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return betterUpgrade(all[i], all[j])
+	})
+
+	limit := options.Limit
+	if limit <= 0 {
+		limit = DefaultUpgradeSuggestions
+	}
+	if limit > MaxUpgradeSuggestions {
+		limit = MaxUpgradeSuggestions
+	}
+
+	advice := &UpgradeAdvice{
+		BuildID:     build.ID.String(),
+		Budget:      options.Budget,
+		Suggestions: []UpgradeSuggestion{},
+	}
+
+	// One suggestion per set of target components, the best ranked wins
+	seen := make(map[string]bool)
+	for _, option := range all {
+		if len(advice.Suggestions) == limit {
+			break
+		}
+
+		key := optionKey(option)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		for i := range option.replacements {
+			option.replacements[i].Cost.Currency = currency
+			option.replacements[i].Cost.Symbol = options.Budget.Symbol
+		}
+
+		summary := summarizeReport(option.report)
+		advice.Suggestions = append(advice.Suggestions, UpgradeSuggestion{
+			Rank:               len(advice.Suggestions) + 1,
+			Replacements:       option.replacements,
+			Cost:               models.PriceItem{Currency: currency, Symbol: options.Budget.Symbol, Amount: roundAmount(option.cost)},
+			ImprovementPercent: roundAmount(option.improvement),
+			Reasons:            option.reasons,
+			Compatibility:      &summary,
+			Power:              option.report.Power,
+		})
+	}
+
+	return advice, nil
+}
+
+// slotUpgrades lists the better parts for one slot, adding the cheapest enabling part
+// when the replacement does not fit the rest of the build on its own
+func (s *UpgradeService) slotUpgrades(parts []BuildPart, index int, candidates map[string][]pricedPart, budget float64, rules []models.CompatibilityRule, baseline *CompatibilityReport) []upgradeOption {
+	current := parts[index]
+	options := []upgradeOption{}
+
+	type scoredCandidate struct {
+		candidate pricedPart
+		from, to  float64
+		basis     string
+	}
+
+	better := []scoredCandidate{}
+	for _, candidate := range candidates[current.CategoryID] {
+		if candidate.part.ID == current.ID || candidate.price*float64(current.Quantity) > budget {
+			continue
+		}
+
+		from, to, basis, ok := compareSpecScores(current, candidate.part)
+		if ok && to > from {
+			better = append(better, scoredCandidate{candidate: candidate, from: from, to: to, basis: basis})
+		}
+	}
+
+	sort.SliceStable(better, func(i, j int) bool {
+		return better[i].to/better[i].from > better[j].to/better[j].from
+	})
+	if len(better) > upgradeCandidatesPerSlot {
+		better = better[:upgradeCandidatesPerSlot]
+	}
+
+	for _, scored := range better {
+		replacement := scored.candidate.part
+		replacement.Quantity = current.Quantity
+		cost := scored.candidate.price * float64(current.Quantity)
+		improvement := (scored.to - scored.from) / scored.from * 100
+
+		option := upgradeOption{
+			replaced:    map[int]BuildPart{index: replacement},
+			cost:        cost,
+			improvement: improvement * upgradeWeights[current.CategoryID],
+			replacements: []UpgradeReplacement{{
+				CategoryID: current.CategoryID,
+				From:       diffComponentOf(current),
+				To:         *diffComponentOf(replacement),
+				Cost:       models.PriceItem{Amount: roundAmount(cost)},
+				ScoreFrom:  roundAmount(scored.from),
+				ScoreTo:    roundAmount(scored.to),
+				ScoreBasis: scored.basis,
+			}},
+			reasons: []string{fmt.Sprintf("%s replaces %s: +%s%% %s", replacement.Name, current.Name, formatAmount(math.Round(improvement)), scored.basis)},
+		}
+
+		if report, ok := acceptUpgrade(applyUpgrade(parts, option.replaced), rules, baseline); ok {
+			option.report = report
+			options = append(options, option)
+			continue
+		}
+
+		if enabled, ok := s.withEnabler(parts, option, candidates, budget, rules, baseline); ok {
+			options = append(options, enabled)
+		}
+	}
+
+	return options
+}
+
+// withEnabler tries the enabling categories of the upgraded part, cheapest part first
+func (s *UpgradeService) withEnabler(parts []BuildPart, option upgradeOption, candidates map[string][]pricedPart, budget float64, rules []models.CompatibilityRule, baseline *CompatibilityReport) (upgradeOption, bool) {
+	var upgraded BuildPart
+	for _, part := range option.replaced {
+		upgraded = part
+	}
+
+	for _, category := range upgradeEnablers[upgraded.CategoryID] {
+		index, current, exists := findPartInCategory(parts, category)
+
+		// Candidates are sorted by price descending
+		pool := candidates[category]
+		for i := len(pool) - 1; i >= 0; i-- {
+			enabler := pool[i]
+			if (exists && enabler.part.ID == current.ID) || option.cost+enabler.price > budget {
+				continue
+			}
+
+			replaced := map[int]BuildPart{}
+			for slot, part := range option.replaced {
+				replaced[slot] = part
+			}
+
+			enablerPart := enabler.part
+			enablerPart.Quantity = 1
+			if exists {
+				replaced[index] = enablerPart
+			} else {
+				replaced[-1] = enablerPart
+			}
+
+			report, ok := acceptUpgrade(applyUpgrade(parts, replaced), rules, baseline)
+			if !ok {
+				continue
+			}
+
+			replacement := UpgradeReplacement{
+				CategoryID: category,
+				To:         *diffComponentOf(enablerPart),
+				Cost:       models.PriceItem{Amount: roundAmount(enabler.price)},
+			}
+			if exists {
+				replacement.From = diffComponentOf(current)
+			}
+
+			enabled := option
+			enabled.replaced = replaced
+			enabled.cost = option.cost + enabler.price
+			enabled.report = report
+			enabled.enabled = true
+			enabled.replacements = append(append([]UpgradeReplacement{}, option.replacements...), replacement)
+			enabled.reasons = append(append([]string{}, option.reasons...), enablerReason(category, upgraded, enablerPart, report))
+			return enabled, true
+		}
+	}
+
+	return upgradeOption{}, false
+}
+
+// pairUpgrades combines two single-slot upgrades of different slots that fit the budget together
+func pairUpgrades(parts []BuildPart, singles []upgradeOption, budget float64, rules []models.CompatibilityRule, baseline *CompatibilityReport) []upgradeOption {
+	pool := []upgradeOption{}
+	for _, option := range singles {
+		if !option.enabled && len(pool) < upgradePairPool {
+			pool = append(pool, option)
+		}
+	}
+
+	pairs := []upgradeOption{}
+	for i := 0; i < len(pool); i++ {
+		for j := i + 1; j < len(pool); j++ {
+			first, second := pool[i], pool[j]
+			if first.cost+second.cost > budget || sharesSlot(first, second) {
+				continue
+			}
+
+			replaced := map[int]BuildPart{}
+			for slot, part := range first.replaced {
+				replaced[slot] = part
+			}
+			for slot, part := range second.replaced {
+				replaced[slot] = part
+			}
+
+			report, ok := acceptUpgrade(applyUpgrade(parts, replaced), rules, baseline)
+			if !ok {
+				continue
+			}
+
+			pairs = append(pairs, upgradeOption{
+				replaced:     replaced,
+				replacements: append(append([]UpgradeReplacement{}, first.replacements...), second.replacements...),
+				cost:         first.cost + second.cost,
+				improvement:  first.improvement + second.improvement,
+				reasons:      append(append([]string{}, first.reasons...), second.reasons...),
+				report:       report,
+			})
+		}
+	}
+
+	return pairs
+}
+
+func (s *UpgradeService) loadUpgradeCandidates(categories []string, currency string) (map[string][]pricedPart, error) {
+	components, err := s.components.GetInStockComponents(categories)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[string][]pricedPart)
+	for _, component := range components {
+		price, ok := models.ParsePrice(component.Price).Find(currency)
+		if !ok {
+			continue
+		}
+
+		candidates[component.CategoryID] = append(candidates[component.CategoryID], pricedPart{
+			part:  BuildPart{ComponentWithRelations: component, Quantity: 1},
+			price: price.Amount,
+		})
+	}
+
+	for category := range candidates {
+		sort.SliceStable(candidates[category], func(i, j int) bool {
+			return candidates[category][i].price > candidates[category][j].price
+		})
+	}
+
+	return candidates, nil
+}
+
+// acceptUpgrade evaluates the upgraded build and rejects new failures and a worse PSU verdict
+func acceptUpgrade(parts []BuildPart, rules []models.CompatibilityRule, baseline *CompatibilityReport) (*CompatibilityReport, bool) {
+	report := EvaluateWithRules(parts, rules)
+	if len(failingOnlyIn(report, baseline)) > 0 {
+		return report, false
+	}
+
+	return report, verdictRank(report.Power.Verdict) <= verdictRank(baseline.Power.Verdict)
+}
+
+func verdictRank(verdict string) int {
+	switch verdict {
+	case VerdictWarn:
+		return 1
+	case VerdictFail:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// applyUpgrade copies the parts with the replacements applied, index -1 adds a part
+func applyUpgrade(parts []BuildPart, replaced map[int]BuildPart) []BuildPart {
+	upgraded := make([]BuildPart, len(parts))
+	copy(upgraded, parts)
+
+	for index, part := range replaced {
+		if index < 0 {
+			upgraded = append(upgraded, part)
+			continue
+		}
+		upgraded[index] = part
+	}
+	return upgraded
+}
+
+// compareSpecScores rates both parts with the first scorer of the category they both support
+func compareSpecScores(current, candidate BuildPart) (float64, float64, string, bool) {
+	for _, scorer := range upgradeScorers[current.CategoryID] {
+		from, fromOK := scorer.score(current.SpecsMap)
+		to, toOK := scorer.score(candidate.SpecsMap)
+		if fromOK && toOK && from > 0 {
+			return from, to, scorer.label, true
+		}
+	}
+	return 0, 0, "", false
+}
+
+// multiplySpecs multiplies the first available value of each key group. Clocks below 100
+// are read as GHz and converted to MHz so "5.6 GHz" and "5600 MHz" compare.
+func multiplySpecs(specs map[string]string, groups ...[]string) (float64, bool) {
+	product := 1.0
+	for _, keys := range groups {
+		value, ok := maxSpecNumber(specs, keys...)
+		if !ok || value <= 0 {
+			return 0, false
+		}
+		if strings.HasSuffix(keys[0], "clock") && value < 100 {
+			value *= 1000
+		}
+		product *= value
+	}
+	return product, true
+}
+
+func enablerReason(category string, upgraded, enabler BuildPart, report *CompatibilityReport) string {
+	switch category {
+	case models.CategoryMainboard:
+		return fmt.Sprintf("%s needs a new mainboard: %s (socket %s)", upgraded.Name, enabler.Name, enabler.SpecsMap["socket"])
+	case models.CategoryPSU:
+		return fmt.Sprintf("%s keeps PSU headroom for an estimated %s W load", enabler.Name, formatAmount(report.Power.EstimatedLoad))
+	case models.CategoryCase:
+		return fmt.Sprintf("%s needs a roomier case: %s", upgraded.Name, enabler.Name)
+	default:
+		return fmt.Sprintf("%s also needs %s", upgraded.Name, enabler.Name)
+	}
+}
+
+func findPartInCategory(parts []BuildPart, category string) (int, BuildPart, bool) {
+	for i, part := range parts {
+		if part.CategoryID == category {
+			return i, part, true
+		}
+	}
+	return -1, BuildPart{}, false
+}
+
+func diffComponentOf(part BuildPart) *DiffComponent {
+	return &DiffComponent{
+		ComponentID: part.ID,
+		Name:        part.Name,
+		CategoryID:  part.CategoryID,
+		Quantity:    part.Quantity,
+	}
+}
+
+func betterUpgrade(a, b upgradeOption) bool {
+	if a.improvement != b.improvement {
+		return a.improvement > b.improvement
+	}
+	return a.cost < b.cost
+}
+
+func sharesSlot(a, b upgradeOption) bool {
+	for slot := range a.replaced {
+		if _, exists := b.replaced[slot]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+func optionKey(option upgradeOption) string {
+	ids := []string{}
+	for _, replacement := range option.replacements {
+		ids = append(ids, replacement.To.ComponentID)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// budgetSymbol takes the currency symbol from the catalog when the request has none
+func budgetSymbol(budget models.PriceItem, candidates map[string][]pricedPart) string {
+	if budget.Symbol != "" {
+		return budget.Symbol
+	}
+
+	for _, pool := range candidates {
+		for _, candidate := range pool {
+			if price, ok := models.ParsePrice(candidate.part.Price).Find(budget.Currency); ok {
+				return price.Symbol
+			}
+		}
+	}
+	return ""
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+const slugAlphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GenerateSlug returns a random URL-safe slug without look-alike characters
+func GenerateSlug(length int) (string, error) {
+	slug := make([]byte, length)
+	alphabetSize := big.NewInt(int64(len(slugAlphabet)))
+
+	for i := range slug {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		slug[i] = slugAlphabet[n.Int64()]
+	}
+
+	return string(slug), nil
+}